@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/bavix/junit-reporter/reporter"
+)
+
+var errBaselineUsage = errors.New(`usage: junit-reporter baseline save|check [flags] FILE [[label=]path ...]`)
+
+func runBaseline(args []string) error {
+	if len(args) == 0 {
+		return errBaselineUsage
+	}
+
+	switch args[0] {
+	case "save":
+		return runBaselineAction("save", "Writes the report to FILE.", args[1:], saveBaseline)
+	case "check":
+		return runBaselineAction("check", "Compares the report with FILE and exits with code 2 when they differ.", args[1:], checkBaseline)
+	default:
+		return errBaselineUsage
+	}
+}
+
+func runBaselineAction(name, help string, args []string, action func(context.Context, string, reporter.Options) error) error {
+	fs := flag.NewFlagSet("baseline "+name, flag.ExitOnError)
+	flags := newReportFlags(fs)
+
+	setUsage(fs, "baseline "+name+" [flags] FILE [[label=]path ...]", help+" Reports are read like in the report command.")
+
+	_ = fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+
+		return errBaselineUsage
+	}
+
+	opts, err := flags.options(fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := flags.context()
+	defer cancel()
+
+	return action(ctx, fs.Arg(0), opts)
+}
+
+func saveBaseline(ctx context.Context, path string, opts reporter.Options) error {
+	out, runErr := runToBytes(ctx, opts)
+
+	var skipped *reporter.SkippedReportsError
+	if runErr != nil && !errors.As(runErr, &skipped) {
+		return runErr
+	}
+
+	err := os.WriteFile(path, append(out, '\n'), baselinePerm)
+	if err != nil {
+		return fmt.Errorf("write baseline: %w", err)
+	}
+
+	fmt.Fprintln(os.Stdout, "wrote baseline:", path)
+
+	return runErr
+}
+
+func checkBaseline(ctx context.Context, path string, opts reporter.Options) error {
+	out, runErr := runToBytes(ctx, opts)
+
+	var skipped *reporter.SkippedReportsError
+	if runErr != nil && !errors.As(runErr, &skipped) {
+		return runErr
+	}
+
+	wantBytes, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read baseline: %w", err)
+	}
+
+	want := bytes.TrimSpace(wantBytes)
+	if bytes.Equal(want, out) {
+		fmt.Fprintln(os.Stdout, "OK: output matches baseline")
+
+		return runErr
+	}
+
+	fmt.Fprintln(os.Stderr, "output mismatch vs baseline:", path)
+	fmt.Fprintln(os.Stderr, "---- baseline ----")
+	fmt.Fprintln(os.Stderr, string(want))
+	fmt.Fprintln(os.Stderr, "---- got ----")
+	fmt.Fprintln(os.Stderr, string(out))
+
+	return errBaselineMismatch
+}
+
+// runToBytes renders the report. With -keep-going the output is returned together with the
+// SkippedReportsError, so the baseline is still written or checked before exiting with it.
+func runToBytes(ctx context.Context, opts reporter.Options) ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := reporter.RunContext(ctx, &buf, opts)
+	if err != nil {
+		return bytes.TrimSpace(buf.Bytes()), fmt.Errorf("run reporter: %w", err)
+	}
+
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
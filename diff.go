@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/bavix/junit-reporter/reporter"
+)
+
+var errDiffUsage = errors.New("usage: junit-reporter diff [flags] OLD NEW")
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	median := fs.Bool("median", false, "Compare medians instead of means")
+	format := fs.String("format", "table", "Output format: table or json")
+	jobs := fs.Int("jobs", runtime.NumCPU(), "Number of reports parsed concurrently")
+	timeout := fs.Duration("timeout", 0, "Abort when reports are not loaded within the duration, e.g. 30s")
+	durations := newDurationFlags(fs)
+
+	setUsage(fs, "diff [flags] OLD NEW",
+		"Compares two JUnit reports, archives or directories regardless of their names. Units are aligned by\n"+
+			"name and their passed durations compared with a Mann-Whitney U test; units added, removed, newly\n"+
+			"failing and newly passing are listed after the table.")
+
+	_ = fs.Parse(args)
+
+	const sides = 2
+
+	if fs.NArg() != sides {
+		fs.Usage()
+
+		return errDiffUsage
+	}
+
+	durationFormat, err := durations.format()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := commandContext(*timeout)
+	defer cancel()
+
+	loader := reporter.Loader{Jobs: *jobs, Progress: nil, Stdin: nil, KeepGoing: false, Cache: nil}
+
+	old, err := loader.Load(ctx, fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("load %s: %w", fs.Arg(0), err)
+	}
+
+	cur, err := loader.Load(ctx, fs.Arg(1))
+	if err != nil {
+		return fmt.Errorf("load %s: %w", fs.Arg(1), err)
+	}
+
+	err = reporter.RenderDiff(os.Stdout, reporter.DiffDatasets(old, cur, *median), *format, durationFormat)
+	if err != nil {
+		return fmt.Errorf("render diff: %w", err)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ module github.com/bavix/junit-reporter
 go 1.25
 
 require (
+	github.com/goccy/go-yaml v1.19.2
 	github.com/hashicorp/go-version v1.8.0
 	github.com/joshdk/go-junit v1.0.0
 	github.com/montanaflynn/stats v0.8.2
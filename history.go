@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/bavix/junit-reporter/reporter"
+)
+
+const defaultHistoryDir = ".junit-history"
+
+var errRegression = errors.New("a unit got slower at a change point")
+
+// defaultCommit returns the commit of the CI build, if the environment provides it.
+func defaultCommit() string {
+	for _, name := range []string{"GITHUB_SHA", "CI_COMMIT_SHA", "GIT_COMMIT"} {
+		if commit := os.Getenv(name); commit != "" {
+			return commit
+		}
+	}
+
+	return ""
+}
+
+func runRecord(args []string) error {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	flags := newReportFlags(fs)
+	dir := fs.String("history-dir", defaultHistoryDir, "Directory of the history store")
+	commit := fs.String("commit", defaultCommit(), "Commit of the run (defaults to $GITHUB_SHA, $CI_COMMIT_SHA or $GIT_COMMIT)")
+
+	setUsage(fs, "record [flags] [[label=]path ...]",
+		"Appends the per-unit aggregates of the reports to the history store as one run, keyed by version,\n"+
+			"commit and -timestamp (defaults to now). Reports are read like in the report command.")
+
+	_ = fs.Parse(args)
+
+	opts, err := flags.options(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := flags.context()
+	defer cancel()
+
+	data, err := reporter.LoadOptions(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("load reports: %w", err)
+	}
+
+	at := opts.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	history := reporter.OpenHistory(*dir)
+
+	n, err := history.Record(reporter.Aggregate(data, opts), *commit, at)
+	if err != nil {
+		return fmt.Errorf("record history: %w", err)
+	}
+
+	fmt.Fprintf(os.Stdout, "recorded %d aggregates to %s\n", n, history.Path())
+
+	if skipped := data.Errors(); len(skipped) > 0 {
+		return &reporter.SkippedReportsError{Errors: skipped}
+	}
+
+	return nil
+}
+
+// historyFlags select records of the history store.
+type historyFlags struct {
+	dir     *string
+	name    *string
+	version *string
+	commit  *string
+	since   *string
+	until   *string
+	last    *int
+}
+
+func newHistoryFlags(fs *flag.FlagSet) *historyFlags {
+	return &historyFlags{
+		dir:     fs.String("history-dir", defaultHistoryDir, "Directory of the history store"),
+		name:    fs.String("name", "", "Unit name or pattern, e.g. Cart:Pay or 'Cart:*'"),
+		version: fs.String("version", "", "Only records of the version"),
+		commit:  fs.String("commit", "", "Only records of the commit"),
+		since:   fs.String("since", "", "Only runs at or after the time, RFC 3339 or unix seconds"),
+		until:   fs.String("until", "", "Only runs at or before the time, RFC 3339 or unix seconds"),
+		last:    fs.Int("last", 0, "Only the last N matching runs"),
+	}
+}
+
+func (f *historyFlags) query() ([]reporter.HistoryRecord, error) {
+	since, err := reporter.ParseTimestamp(*f.since)
+	if err != nil {
+		return nil, fmt.Errorf("parse -since: %w", err)
+	}
+
+	until, err := reporter.ParseTimestamp(*f.until)
+	if err != nil {
+		return nil, fmt.Errorf("parse -until: %w", err)
+	}
+
+	records, err := reporter.OpenHistory(*f.dir).Query(reporter.HistoryQuery{
+		Name:    *f.name,
+		Version: *f.version,
+		Commit:  *f.commit,
+		Since:   since,
+		Until:   until,
+		Last:    *f.last,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query history: %w", err)
+	}
+
+	return records, nil
+}
+
+func runHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	flags := newHistoryFlags(fs)
+	format := fs.String("format", "table", "Output format: table, csv or jsonl")
+	durations := newDurationFlags(fs)
+
+	setUsage(fs, "history [flags]", "Prints recorded runs from the history store, oldest first.")
+
+	_ = fs.Parse(args)
+
+	durationFormat, err := durations.format()
+	if err != nil {
+		return err
+	}
+
+	records, err := flags.query()
+	if err != nil {
+		return err
+	}
+
+	err = reporter.RenderHistory(os.Stdout, records, *format, durationFormat)
+	if err != nil {
+		return fmt.Errorf("render history: %w", err)
+	}
+
+	return nil
+}
+
+func runChanges(args []string) error {
+	fs := flag.NewFlagSet("changes", flag.ExitOnError)
+	flags := newHistoryFlags(fs)
+	median := fs.Bool("median", false, "Compare run medians instead of run means")
+	minSegment := fs.Int("min-segment", 0, "Smallest number of runs on either side of a change (default 3)")
+	threshold := fs.Float64("threshold", 0, "Smallest t statistic of a change (default 5)")
+	minChange := fs.Float64("min-change", 0, "Smallest relative change in percent (default 5)")
+	format := fs.String("format", "table", "Output format: table or json")
+	fail := fs.Bool("fail", false, "Exit with code 4 when a unit is slower in its latest segment than in the one before")
+	durations := newDurationFlags(fs)
+
+	setUsage(fs, "changes [flags]",
+		"Detects step changes in the duration of every unit and version across the runs of the history\n"+
+			"store and prints them as a \"Change points\" section. With -fail it gates CI on units that are\n"+
+			"slower since their latest change point; slow-downs that were fixed since do not fail.")
+
+	_ = fs.Parse(args)
+
+	durationFormat, err := durations.format()
+	if err != nil {
+		return err
+	}
+
+	records, err := flags.query()
+	if err != nil {
+		return err
+	}
+
+	points := reporter.DetectChangePoints(records, reporter.ChangePointOptions{
+		Median:     *median,
+		MinSegment: *minSegment,
+		Threshold:  *threshold,
+		MinChange:  *minChange,
+	})
+
+	err = reporter.RenderChangePoints(os.Stdout, points, *format, durationFormat)
+	if err != nil {
+		return fmt.Errorf("render change points: %w", err)
+	}
+
+	if *fail && reporter.LatestRegression(points) {
+		return errRegression
+	}
+
+	return nil
+}
+
+// runReportChanges renders the report like RunContext and appends the change points of its
+// units and versions found in the history store, detected with the default options.
+func runReportChanges(ctx context.Context, opts reporter.Options, dir string) error {
+	err := opts.Validate()
+	if err != nil {
+		return fmt.Errorf("run reporter: %w", err)
+	}
+
+	durations, err := reporter.ParseDurationFormat(opts.DurationUnit, opts.DurationPrecision)
+	if err != nil {
+		return fmt.Errorf("run reporter: %w", err)
+	}
+
+	data, err := reporter.LoadOptions(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("run reporter: %w", err)
+	}
+
+	report := reporter.Aggregate(data, opts)
+
+	err = opts.ValidateSort(report.Versions)
+	if err != nil {
+		return fmt.Errorf("run reporter: %w", err)
+	}
+
+	err = reporter.Emit(os.Stdout, report)
+	if err != nil {
+		return fmt.Errorf("run reporter: %w", err)
+	}
+
+	err = writeChangesSection(os.Stdout, dir, report, durations)
+	if err != nil {
+		return err
+	}
+
+	if skipped := data.Errors(); len(skipped) > 0 {
+		return &reporter.SkippedReportsError{Errors: skipped}
+	}
+
+	return nil
+}
+
+// writeChangesSection appends the change points of the units and versions of a report.
+func writeChangesSection(w io.Writer, dir string, report *reporter.Report, durations reporter.DurationFormat) error {
+	records, err := reporter.OpenHistory(dir).Query(reporter.HistoryQuery{
+		Name: "", Version: "", Commit: "", Since: time.Time{}, Until: time.Time{}, Last: 0,
+	})
+	if err != nil {
+		return fmt.Errorf("query history: %w", err)
+	}
+
+	points := report.ChangePoints(records, reporter.ChangePointOptions{
+		Median: false, MinSegment: 0, Threshold: 0, MinChange: 0,
+	})
+
+	_, err = io.WriteString(w, "\n")
+	if err != nil {
+		return fmt.Errorf("write change points: %w", err)
+	}
+
+	err = reporter.RenderChangePoints(w, points, "table", durations)
+	if err != nil {
+		return fmt.Errorf("render change points: %w", err)
+	}
+
+	return nil
+}
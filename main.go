@@ -1,135 +1,124 @@
 package main
 
 import (
-	"bytes"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
 
-	"github.com/bavix/junit-reporter/internal/reporter"
+	"github.com/bavix/junit-reporter/reporter"
 )
 
-const baselinePerm = 0o600
-
-func main() {
-	ticks := flag.Bool("ticks", false, "Time per ticks")
-	group := flag.Bool("group", false, "Groups by version")
-	major := flag.Bool("major", false, "Can only be used with a group")
-	median := flag.Bool("median", false, "Median search")
-	rotate := flag.Bool("rotate", false, "Swap versions and names")
-	directory := flag.String("path", "./build", "Specify folder path")
-	compare := flag.String("compare", "", "Path to baseline file to compare output against")
-	generate := flag.String("generate-baseline", "", "Write current output to given file path and exit")
-
-	flag.Parse()
-
-	opts := reporter.Options{
-		Directory:    *directory,
-		Ticks:        *ticks,
-		Group:        *group,
-		Major:        *major,
-		Median:       *median,
-		Rotate:       *rotate,
-		OutputFormat: "",
-		OutputFile:   "",
-	}
+const (
+	baselinePerm = 0o600
+	// exitCodeMismatch signals that the output differs from the baseline.
+	exitCodeMismatch = 2
+	// exitCodeSkipped signals that -keep-going left unreadable reports out.
+	exitCodeSkipped = 3
+	// exitCodeRegression signals that "changes -fail" found a unit that got slower.
+	exitCodeRegression = 4
+)
 
-	const exitCodeMismatch = 2
+var errBaselineMismatch = errors.New("output does not match baseline")
 
-	handled, err := handleCompareGenerate(*compare, *generate, opts, exitCodeMismatch)
-	if err != nil {
-		log.Fatalln(err)
-	}
+// command is a subcommand of the CLI; run receives the arguments after its name.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
 
-	if handled {
-		return
+func commands() []command {
+	return []command{
+		{name: "report", summary: "Render the report (default when no command is given)", run: runReport},
+		{name: "baseline", summary: "Save the report as a baseline file or check it against one", run: runBaseline},
+		{name: "diff", summary: "Compare two reports or directories unit by unit", run: runDiff},
+		{name: "record", summary: "Append the aggregates of a run to the history store", run: runRecord},
+		{name: "history", summary: "Query runs recorded in the history store", run: runHistory},
+		{name: "serve", summary: "Serve an interactive dashboard and JSON API over the reports", run: runServe},
+		{name: "changes", summary: "Detect step changes in durations across recorded runs", run: runChanges},
 	}
+}
 
-	err = reporter.Run(os.Stdout, opts)
-	if err != nil {
+func main() {
+	err := run(os.Args[1:])
+
+	var skipped *reporter.SkippedReportsError
+
+	switch {
+	case err == nil:
+	case errors.As(err, &skipped):
+		printSkipped(skipped)
+		os.Exit(exitCodeSkipped)
+	case errors.Is(err, errBaselineMismatch):
+		os.Exit(exitCodeMismatch)
+	case errors.Is(err, errRegression):
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitCodeRegression)
+	default:
 		log.Fatalln(err)
 	}
 }
 
-func handleCompareGenerate(compare, generate string, opts reporter.Options, exitCode int) (bool, error) {
-	if generate != "" {
-		out, err := runToBytes(opts)
-		if err != nil {
-			return true, err
-		}
-
-		err = writeBaseline(generate, out)
-		if err != nil {
-			return true, err
-		}
-
-		fmt.Fprintln(os.Stdout, "wrote baseline:", generate)
-
-		return true, nil
+// run dispatches to the command named by the first argument. Without a command the
+// arguments are report flags, so invocations from before subcommands keep working.
+func run(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runReport(args)
 	}
 
-	if compare != "" {
-		out, err := runToBytes(opts)
-		if err != nil {
-			return true, err
-		}
-
-		var match bool
+	if args[0] == "help" {
+		printCommands(os.Stdout)
 
-		match, err = compareBaseline(compare, out)
-		if err != nil {
-			return true, err
-		}
-
-		if match {
-			fmt.Fprintln(os.Stdout, "OK: output matches baseline")
+		return nil
+	}
 
-			return true, nil
+	for _, cmd := range commands() {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
 		}
+	}
 
-		os.Exit(exitCode)
+	// a report source given without flags, e.g. "junit-reporter ./build"
+	_, err := os.Stat(args[0])
+	if err == nil || args[0] == "-" || strings.Contains(args[0], "=") {
+		return runReport(args)
 	}
 
-	return false, nil
+	printCommands(os.Stderr)
+
+	return fmt.Errorf("unknown command %q", args[0])
 }
 
-func runToBytes(opts reporter.Options) ([]byte, error) {
-	var buf bytes.Buffer
+func printCommands(w io.Writer) {
+	fmt.Fprintln(w, "Usage: junit-reporter [command] [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
 
-	err := reporter.Run(&buf, opts)
-	if err != nil {
-		return nil, fmt.Errorf("run reporter: %w", err)
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
 	}
 
-	return bytes.TrimSpace(buf.Bytes()), nil
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "junit-reporter <command> -h" for the flags of a command.`)
 }
 
-func writeBaseline(path string, out []byte) error {
-	err := os.WriteFile(path, append(out, '\n'), baselinePerm)
-	if err != nil {
-		return fmt.Errorf("write baseline: %w", err)
+// setUsage sets the help text of a command flag set.
+func setUsage(fs *flag.FlagSet, synopsis, help string) {
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: junit-reporter %s\n\n%s\n\nFlags:\n", synopsis, help)
+		fs.PrintDefaults()
 	}
-
-	return nil
 }
 
-func compareBaseline(path string, out []byte) (bool, error) {
-	wantBytes, err := os.ReadFile(path)
-	if err != nil {
-		return false, fmt.Errorf("read baseline: %w", err)
-	}
+// printSkipped writes the diagnostic section listing reports left out by -keep-going.
+func printSkipped(skipped *reporter.SkippedReportsError) {
+	fmt.Fprintf(os.Stderr, "\n%s:\n", skipped.Error())
 
-	want := bytes.TrimSpace(wantBytes)
-	if bytes.Equal(want, out) {
-		return true, nil
+	for _, err := range skipped.Errors {
+		fmt.Fprintln(os.Stderr, "  "+err.Error())
 	}
-
-	fmt.Fprintln(os.Stderr, "output mismatch vs baseline:", path)
-	fmt.Fprintln(os.Stderr, "---- baseline ----")
-	fmt.Fprintln(os.Stderr, string(want))
-	fmt.Fprintln(os.Stderr, "---- got ----")
-	fmt.Fprintln(os.Stderr, string(out))
-
-	return false, nil
 }
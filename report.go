@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/bavix/junit-reporter/reporter"
+)
+
+// reportFlags are the flags shared by the commands that render a report.
+type reportFlags struct {
+	ticks        *bool
+	group        *bool
+	major        *bool
+	median       *bool
+	rotate       *bool
+	directory    *string
+	outputFormat *string
+	outputFile   *string
+	tmpl         *string
+	timestamp    *string
+	timeout      *time.Duration
+	progress     *bool
+	keepGoing    *bool
+	version      *string
+	jobs         *int
+	outputs      []reporter.Output
+	filters      []reporter.Filter
+	constraint   *string
+	prerelease   *bool
+	lastVersions *int
+	versionOrder *string
+	sortBy       *string
+	sortOrder    *string
+	top          *int
+	durations    durationFlags
+	alignRight   *bool
+}
+
+func newReportFlags(fs *flag.FlagSet) *reportFlags {
+	formats := strings.Join(reporter.FormatNames(), ", ")
+
+	flags := &reportFlags{
+		ticks:        fs.Bool("ticks", false, "Time per ticks"),
+		group:        fs.Bool("group", false, "Groups by version"),
+		major:        fs.Bool("major", false, "Can only be used with a group"),
+		median:       fs.Bool("median", false, "Median search"),
+		rotate:       fs.Bool("rotate", false, "Swap versions and names"),
+		directory:    fs.String("path", "./build", "Specify folder path with junit-*.xml(.gz) reports or .zip/.tar.gz archives"),
+		outputFormat: fs.String("output-format", "", "Export format: "+formats),
+		outputFile:   fs.String("output-file", "", "Path of the export file (defaults to <path>/report.<ext>)"),
+		tmpl:         fs.String("template", "", "Render the report with a Go template file instead of the table"),
+		timestamp:    fs.String("timestamp", "", "Timestamp of time-series points, RFC 3339 or unix seconds"),
+		timeout:      fs.Duration("timeout", 0, "Abort when reports are not loaded within the duration, e.g. 30s"),
+		progress:     fs.Bool("progress", stderrIsTerminal(), "Print loading progress to stderr"),
+		keepGoing:    fs.Bool("keep-going", false, "Skip unreadable reports, list them on stderr and exit with code 3"),
+		version:      fs.String("version", "", "Version label of the report read from stdin (-)"),
+		jobs:         fs.Int("jobs", runtime.NumCPU(), "Number of reports parsed concurrently"),
+		outputs:      nil,
+		filters:      nil,
+		constraint:   fs.String("constraint", "", "Show only versions matching a constraint, e.g. \">= 7.0, < 10\""),
+		prerelease:   fs.Bool("exclude-prerelease", false, "Leave out pre-release versions such as 7.3.0-beta1"),
+		lastVersions: fs.Int("last-versions", 0, "Show only the last N of the selected versions"),
+		versionOrder: fs.String("version-order", "", "Comma-separated versions, e.g. branch names, shown first in this order"),
+		sortBy:       fs.String("sort", "", "Order units by name, duration[:VER], delta[:OLD..NEW], variance or failures"),
+		sortOrder:    fs.String("sort-order", "", "Sort asc or desc; defaults to asc for name and desc otherwise"),
+		top:          fs.Int("top", 0, "Show only the first N units in sort order"),
+		durations:    newDurationFlags(fs),
+		alignRight:   fs.Bool("align-right", false, "Align durations to the right in table, trend and rst output"),
+	}
+
+	fs.Func("output", "Render to format[=path], repeatable, stdout without a path; formats: "+formats, func(spec string) error {
+		flags.outputs = append(flags.outputs, reporter.ParseOutput(spec))
+
+		return nil
+	})
+
+	for _, side := range []struct {
+		name, usage string
+		exclude     bool
+	}{
+		{name: "include", usage: "Keep only units matching [name|class|method|namespace:]glob or /regexp/, repeatable", exclude: false},
+		{name: "exclude", usage: "Leave out units matching [name|class|method|namespace:]glob or /regexp/, repeatable", exclude: true},
+	} {
+		fs.Func(side.name, side.usage, func(spec string) error {
+			filter, err := reporter.ParseFilter(spec, side.exclude)
+			if err != nil {
+				return fmt.Errorf("parse %s: %w", side.name, err)
+			}
+
+			flags.filters = append(flags.filters, filter)
+
+			return nil
+		})
+	}
+
+	return flags
+}
+
+// durationFlags format the durations of the commands printing them.
+type durationFlags struct {
+	unit      *string
+	precision *string
+}
+
+func newDurationFlags(fs *flag.FlagSet) durationFlags {
+	return durationFlags{
+		unit:      fs.String("unit", "", "Print durations in a fixed unit: ns, us, ms or s"),
+		precision: fs.String("precision", "", "Duration precision: N significant digits or .N decimals (default 3 digits)"),
+	}
+}
+
+func (f durationFlags) format() (reporter.DurationFormat, error) {
+	format, err := reporter.ParseDurationFormat(*f.unit, *f.precision)
+	if err != nil {
+		return format, fmt.Errorf("parse duration format: %w", err)
+	}
+
+	return format, nil
+}
+
+// options builds the report options; inputs are the positional report sources.
+func (f *reportFlags) options(inputs []string) (reporter.Options, error) {
+	stamp, err := reporter.ParseTimestamp(*f.timestamp)
+	if err != nil {
+		return reporter.Options{}, fmt.Errorf("parse timestamp: %w", err)
+	}
+
+	opts := reporter.Options{
+		Directory:         *f.directory,
+		Ticks:             *f.ticks,
+		Group:             *f.group,
+		Major:             *f.major,
+		Median:            *f.median,
+		Rotate:            *f.rotate,
+		OutputFormat:      *f.outputFormat,
+		OutputFile:        *f.outputFile,
+		Timestamp:         stamp,
+		Template:          *f.tmpl,
+		Outputs:           f.outputs,
+		Progress:          nil,
+		Jobs:              *f.jobs,
+		Inputs:            stdinLabeled(inputs, *f.version),
+		KeepGoing:         *f.keepGoing,
+		Filters:           f.filters,
+		VersionConstraint: *f.constraint,
+		ExcludePrerelease: *f.prerelease,
+		LastVersions:      *f.lastVersions,
+		VersionOrder:      splitList(*f.versionOrder),
+		SortBy:            *f.sortBy,
+		SortOrder:         *f.sortOrder,
+		Top:               *f.top,
+		DurationUnit:      *f.durations.unit,
+		DurationPrecision: *f.durations.precision,
+		AlignRight:        *f.alignRight,
+	}
+
+	err = opts.Validate()
+	if err != nil {
+		return reporter.Options{}, fmt.Errorf("check options: %w", err)
+	}
+
+	if *f.progress {
+		opts.Progress = printProgress
+	}
+
+	return opts, nil
+}
+
+// context is cancelled on Ctrl-C and, with -timeout, once the timeout passes.
+func (f *reportFlags) context() (context.Context, context.CancelFunc) {
+	return commandContext(*f.timeout)
+}
+
+// commandContext is cancelled on Ctrl-C and, when timeout is positive, once it passes.
+func commandContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	if timeout <= 0 {
+		return ctx, stop
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+func runReport(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	flags := newReportFlags(fs)
+	config := fs.String("config", "junit-reporter.yaml", "Path of the YAML config with report profiles")
+	profile := fs.String("profile", "", "Render the named profile of the config instead of the report flags")
+	allProfiles := fs.Bool("all-profiles", false, "Render every profile of the config to its configured outputs")
+	compare := fs.String("compare", "", "Alias of \"baseline check\": compare the output against a baseline file")
+	generate := fs.String("generate-baseline", "", "Alias of \"baseline save\": write the output to a baseline file")
+	watch := fs.Bool("watch", false, "Render again whenever reports are added, changed or removed, until Ctrl-C")
+	interval := fs.Duration("watch-interval", defaultWatchInterval, "How often -watch checks the reports")
+	changes := fs.Bool("changes", false, "Append a \"Change points\" section detected in the history store")
+	historyDir := fs.String("history-dir", defaultHistoryDir, "Directory of the history store read by -changes")
+
+	setUsage(fs, "report [flags] [[label=]path ...]",
+		"Renders the report. Reads reports from the arguments, \"-\" for stdin, or from -path when none are given.")
+
+	_ = fs.Parse(args)
+
+	opts, err := flags.options(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	if *watch && *interval <= 0 {
+		return fmt.Errorf("-watch-interval: %w: %s", reporter.ErrInvalidWatchInterval, *interval)
+	}
+
+	ctx, cancel := flags.context()
+	defer cancel()
+
+	switch {
+	case *profile != "" || *allProfiles:
+		return runProfiles(ctx, *config, *profile, opts.Progress)
+	case *generate != "":
+		return saveBaseline(ctx, *generate, opts)
+	case *compare != "":
+		return checkBaseline(ctx, *compare, opts)
+	case *watch:
+		return runWatch(ctx, opts, *interval)
+	}
+
+	if *changes {
+		return runReportChanges(ctx, opts, *historyDir)
+	}
+
+	err = reporter.RunContext(ctx, os.Stdout, opts)
+	if err != nil {
+		return fmt.Errorf("run reporter: %w", err)
+	}
+
+	return nil
+}
+
+// runProfiles renders the named profile, or every profile when name is empty.
+func runProfiles(ctx context.Context, config, name string, progress reporter.ProgressFunc) error {
+	cfg, err := reporter.LoadConfig(config)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	names := []string{name}
+	if name == "" {
+		names = cfg.Names()
+	}
+
+	for _, profile := range names {
+		err = cfg.Run(ctx, os.Stdout, profile, progress)
+		if err != nil {
+			return fmt.Errorf("run profile %s: %w", profile, err)
+		}
+	}
+
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// stdinLabeled gives the stdin source "-" the -version label.
+func stdinLabeled(args []string, version string) []string {
+	inputs := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		if arg == "-" && version != "" {
+			arg = version + "=-"
+		}
+
+		inputs = append(inputs, arg)
+	}
+
+	return inputs
+}
+
+func stderrIsTerminal() bool {
+	return isTerminal(os.Stderr)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// printProgress keeps a single progress line on stderr and clears it once all reports are parsed.
+func printProgress(p reporter.Progress) {
+	fmt.Fprintf(os.Stderr, "\r\033[Kparsed %d/%d files, %d tests", p.Files, p.TotalFiles, p.Tests)
+
+	if p.Files == p.TotalFiles {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+}
@@ -0,0 +1,191 @@
+package reporter
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+// percentScale turns a relative change of the statistics into percent, as shown by diffs,
+// trends, change points and templates.
+const percentScale = 100
+
+// UnitStats holds raw statistics of a single unit for a single version.
+// Unlike the table cells it keeps numeric values, so exporters aimed at
+// machines can format them on their own.
+type UnitStats struct {
+	Class   string
+	Method  string
+	Name    string
+	Version string
+	Label   string
+	Time    time.Time
+	Sum     time.Duration
+	Mean    time.Duration
+	Median  time.Duration
+	Min     time.Duration
+	Max     time.Duration
+	Passed  int
+	Failed  int
+	Skipped int
+	Errors  int
+}
+
+// Total returns the number of samples regardless of their status.
+func (a UnitStats) Total() int {
+	return a.Passed + a.Failed + a.Skipped + a.Errors
+}
+
+// HasDurations reports whether at least one passed sample contributed to the statistics.
+func (a UnitStats) HasDurations() bool {
+	return a.Passed > 0
+}
+
+// ShortClass returns the class name without the "Test" suffix, as used in FullName.
+func (u *unit) ShortClass() string {
+	return strings.TrimSuffix(u.Class, "Test")
+}
+
+// ShortMethod returns the method name without the "test" prefix, as used in FullName.
+func (u *unit) ShortMethod() string {
+	return strings.TrimPrefix(u.Method, "test")
+}
+
+// Aggregate collects statistics over all samples of the given version.
+// Durations are computed over passed samples only; other statuses are counted.
+func (u *unit) Aggregate(ver string) UnitStats {
+	return u.aggregateWhere(ver, "", func(sample uTest) bool {
+		return sample.Ver == ver
+	})
+}
+
+// AggregateLabel collects statistics over the samples of a single source report.
+func (u *unit) AggregateLabel(ver, label string) UnitStats {
+	return u.aggregateWhere(ver, label, func(sample uTest) bool {
+		return sample.Ver == ver && sample.Label == label
+	})
+}
+
+func (u *unit) aggregateWhere(ver, label string, match func(sample uTest) bool) UnitStats {
+	agg := UnitStats{
+		Class:   u.ShortClass(),
+		Method:  u.ShortMethod(),
+		Name:    u.FullName(),
+		Version: ver,
+		Label:   label,
+		Time:    time.Time{},
+		Sum:     0,
+		Mean:    0,
+		Median:  0,
+		Min:     0,
+		Max:     0,
+		Passed:  0,
+		Failed:  0,
+		Skipped: 0,
+		Errors:  0,
+	}
+
+	var passed []time.Duration
+
+	for _, testCase := range u.t {
+		if !match(testCase) {
+			continue
+		}
+
+		if testCase.Time.After(agg.Time) {
+			agg.Time = testCase.Time
+		}
+
+		switch testCase.JUnit.Status {
+		case junit.StatusPassed:
+			agg.Passed++
+
+			passed = append(passed, testCase.JUnit.Duration)
+		case junit.StatusFailed:
+			agg.Failed++
+		case junit.StatusSkipped:
+			agg.Skipped++
+		case junit.StatusError:
+			agg.Errors++
+		}
+	}
+
+	if len(passed) == 0 {
+		return agg
+	}
+
+	agg.Sum = u.getDurationSum(passed)
+	agg.Mean = u.getDurationAverage(passed)
+	agg.Median = u.getDurationMedian(passed)
+	agg.Min = slices.Min(passed)
+	agg.Max = slices.Max(passed)
+
+	return agg
+}
+
+func sortedUnitKeys(units map[string]*unit) []string {
+	unitList := make([]string, 0, len(units))
+	for key := range units {
+		unitList = append(unitList, key)
+	}
+
+	slices.Sort(unitList)
+
+	return unitList
+}
+
+// buildAggregates returns statistics for every unit/version pair that has samples,
+// ordered by unit name and then by the given version order.
+func buildAggregates(units map[string]*unit, versions []string) []UnitStats {
+	unitList := sortedUnitKeys(units)
+
+	aggs := make([]UnitStats, 0, len(unitList)*len(versions))
+
+	for _, unitKey := range unitList {
+		for _, ver := range versions {
+			agg := units[unitKey].Aggregate(ver)
+			if agg.Total() == 0 {
+				continue
+			}
+
+			aggs = append(aggs, agg)
+		}
+	}
+
+	return aggs
+}
+
+// buildSeries returns statistics per unit, version and source report label, which is
+// the granularity time-series stores expect: every report is a separate point.
+func buildSeries(units map[string]*unit, versions []string) []UnitStats {
+	unitList := sortedUnitKeys(units)
+
+	var aggs []UnitStats
+
+	for _, unitKey := range unitList {
+		unitVal := units[unitKey]
+
+		for _, ver := range versions {
+			for _, label := range unitVal.labels(ver) {
+				aggs = append(aggs, unitVal.AggregateLabel(ver, label))
+			}
+		}
+	}
+
+	return aggs
+}
+
+// labels returns the distinct source labels of the given version in ingestion order.
+func (u *unit) labels(ver string) []string {
+	var labels []string
+
+	for _, testCase := range u.t {
+		if testCase.Ver == ver && !slices.Contains(labels, testCase.Label) {
+			labels = append(labels, testCase.Label)
+		}
+	}
+
+	return labels
+}
@@ -0,0 +1,175 @@
+package reporter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// archiveSeparator joins an archive path and the path of a report inside it.
+const archiveSeparator = "!/"
+
+// isReportName reports whether a file or archive entry name looks like a JUnit report:
+// junit-*.xml, optionally gzip-compressed.
+func isReportName(name string) bool {
+	base := path.Base(name)
+
+	return strings.HasPrefix(base, "junit-") && (strings.HasSuffix(base, ".xml") || strings.HasSuffix(base, ".xml.gz"))
+}
+
+// isArchiveName reports whether a file is a zip or gzip-compressed tar bundle of reports.
+func isArchiveName(name string) bool {
+	return strings.HasSuffix(name, ".zip") || strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz")
+}
+
+// loadPath parses a report file or every report inside an archive. Archived reports get
+// the path "<archive>!/<entry>" and take their version from the entry path alone. With
+// keepGoing an unreadable entry is skipped and returned with the reports of the others;
+// otherwise it fails the whole archive.
+func loadPath(ctx context.Context, filePath string, keepGoing bool) ([]loadedFile, []*ReportError, error) {
+	switch {
+	case strings.HasSuffix(filePath, ".zip"):
+		return loadZip(ctx, filePath, keepGoing)
+	case strings.HasSuffix(filePath, ".tar.gz"), strings.HasSuffix(filePath, ".tgz"):
+		return loadTarGz(ctx, filePath, keepGoing)
+	default:
+		file, err := loadFile(ctx, filePath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return []loadedFile{file}, nil, nil
+	}
+}
+
+// skipEntry records the error of an archive entry with keepGoing and reports whether loading
+// the archive goes on; a done context always stops it.
+func skipEntry(ctx context.Context, skipped []*ReportError, err error, keepGoing bool) ([]*ReportError, bool) {
+	if !keepGoing || ctx.Err() != nil {
+		return skipped, false
+	}
+
+	var reportErr *ReportError
+	if !errors.As(err, &reportErr) {
+		return skipped, false
+	}
+
+	return append(skipped, reportErr), true
+}
+
+func loadZip(ctx context.Context, archivePath string, keepGoing bool) ([]loadedFile, []*ReportError, error) {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, nil, newReportError(archivePath, err)
+	}
+	defer archive.Close()
+
+	var (
+		files   []loadedFile
+		skipped []*ReportError
+	)
+
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() || !isReportName(entry.Name) {
+			continue
+		}
+
+		file, err := loadZipEntry(ctx, archivePath, entry)
+		if err != nil {
+			var ok bool
+
+			skipped, ok = skipEntry(ctx, skipped, err, keepGoing)
+			if !ok {
+				return nil, nil, err
+			}
+
+			continue
+		}
+
+		files = append(files, file)
+	}
+
+	return files, skipped, nil
+}
+
+func loadZipEntry(ctx context.Context, archivePath string, entry *zip.File) (loadedFile, error) {
+	r, err := entry.Open()
+	if err != nil {
+		return loadedFile{}, newReportError(archivePath+archiveSeparator+entry.Name, err)
+	}
+	defer r.Close()
+
+	return parseEntry(ctx, r, archivePath+archiveSeparator+entry.Name, entry.Name)
+}
+
+func loadTarGz(ctx context.Context, archivePath string, keepGoing bool) ([]loadedFile, []*ReportError, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, nil, newReportError(archivePath, err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, newReportError(archivePath, err)
+	}
+	defer gz.Close()
+
+	var (
+		files   []loadedFile
+		skipped []*ReportError
+	)
+
+	archive := tar.NewReader(gz)
+
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return files, skipped, nil
+		}
+
+		// a broken tar stream cannot be read past, unlike a broken entry
+		if err != nil {
+			return nil, nil, newReportError(archivePath, err)
+		}
+
+		if header.Typeflag != tar.TypeReg || !isReportName(header.Name) {
+			continue
+		}
+
+		file, err := parseEntry(ctx, archive, archivePath+archiveSeparator+header.Name, header.Name)
+		if err != nil {
+			var ok bool
+
+			skipped, ok = skipEntry(ctx, skipped, err, keepGoing)
+			if !ok {
+				return nil, nil, err
+			}
+
+			continue
+		}
+
+		files = append(files, file)
+	}
+}
+
+// parseEntry parses a report read from r, decompressing it when name ends with .gz.
+func parseEntry(ctx context.Context, r io.Reader, filePath, name string) (loadedFile, error) {
+	if !strings.HasSuffix(name, ".gz") {
+		return parseReport(ctx, r, filePath, name)
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return loadedFile{}, newReportError(filePath, err)
+	}
+	defer gz.Close()
+
+	return parseReport(ctx, gz, filePath, name)
+}
@@ -0,0 +1,311 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMinSegment = 3
+	defaultThreshold  = 5
+	defaultMinChange  = 5
+	// changeSides are the segments before and after a change.
+	changeSides = 2
+)
+
+// ChangePointOptions tune DetectChangePoints. Zero values select the defaults.
+type ChangePointOptions struct {
+	// Median compares run medians instead of run means.
+	Median bool
+	// MinSegment is the smallest number of runs on either side of a change, 3 by default.
+	MinSegment int
+	// Threshold is the smallest t statistic of a change, 5 by default.
+	Threshold float64
+	// MinChange is the smallest relative change in percent, 5 by default.
+	MinChange float64
+}
+
+// ChangePoint is a step change in the durations of a unit and version: the runs from Time
+// on differ from the runs before it.
+type ChangePoint struct {
+	Name    string    `json:"name"`
+	Version string    `json:"version"`
+	Time    time.Time `json:"time"`
+	Commit  string    `json:"commit,omitempty"`
+	// Before and After are the averages of the run values of the segments on either side of
+	// the change, up to the neighbouring change points.
+	Before  time.Duration `json:"before"`
+	After   time.Duration `json:"after"`
+	Percent float64       `json:"percent"`
+	// Score is the t statistic of the split.
+	Score float64 `json:"score"`
+	// Latest marks the change into the most recent segment of the series.
+	Latest bool `json:"latest"`
+}
+
+// Regression reports whether the unit got slower at the change.
+func (c ChangePoint) Regression() bool {
+	return c.After > c.Before
+}
+
+// LatestRegression reports whether a unit is slower in the most recent segment of its series
+// than in the segment before it. Earlier slow-downs that were fixed since do not count.
+func LatestRegression(points []ChangePoint) bool {
+	return slices.ContainsFunc(points, func(point ChangePoint) bool {
+		return point.Latest && point.Regression()
+	})
+}
+
+func (o ChangePointOptions) withDefaults() ChangePointOptions {
+	if o.MinSegment < 1 {
+		o.MinSegment = defaultMinSegment
+	}
+
+	if o.Threshold <= 0 {
+		o.Threshold = defaultThreshold
+	}
+
+	if o.MinChange <= 0 {
+		o.MinChange = defaultMinChange
+	}
+
+	return o
+}
+
+// ChangePoints detects the change points of the units and versions shown in the report, so
+// the history of units left out by filters, version selection or Top is ignored.
+func (r *Report) ChangePoints(records []HistoryRecord, opts ChangePointOptions) []ChangePoint {
+	shown := make([]HistoryRecord, 0, len(records))
+
+	for _, record := range records {
+		if _, ok := r.units[record.Name]; ok && slices.Contains(r.Versions, record.Version) {
+			shown = append(shown, record)
+		}
+	}
+
+	return DetectChangePoints(shown, opts)
+}
+
+// DetectChangePoints finds step changes in the series of every unit and version of the
+// history records, which must be ordered by time as History.Query returns them. Series are
+// split recursively at the run that maximises the two-sample t statistic (binary
+// segmentation) while the split passes Threshold and MinChange, so gradual drifts show up
+// as one or more steps. Runs without passed samples are ignored.
+func DetectChangePoints(records []HistoryRecord, opts ChangePointOptions) []ChangePoint {
+	opts = opts.withDefaults()
+
+	series := map[string][]HistoryRecord{}
+
+	var keys []string
+
+	for _, record := range records {
+		if record.Passed == 0 {
+			continue
+		}
+
+		key := record.Name + "\x00" + record.Version
+		if _, ok := series[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		series[key] = append(series[key], record)
+	}
+
+	slices.Sort(keys)
+
+	var points []ChangePoint
+
+	for _, key := range keys {
+		runs := series[key]
+
+		values := make([]float64, 0, len(runs))
+		for _, run := range runs {
+			value := run.Mean
+			if opts.Median {
+				value = run.Median
+			}
+
+			values = append(values, float64(value))
+		}
+
+		splits := segment(values, 0, len(values), opts)
+
+		for idx, split := range splits {
+			// neighbouring changes bound the segments compared by the change point
+			lo, hi := 0, len(values)
+			if idx > 0 {
+				lo = splits[idx-1].split
+			}
+
+			if idx < len(splits)-1 {
+				hi = splits[idx+1].split
+			}
+
+			point := newChangePoint(runs, values, changeSplit{lo: lo, split: split.split, hi: hi, score: split.score})
+			point.Latest = idx == len(splits)-1
+			points = append(points, point)
+		}
+	}
+
+	return points
+}
+
+// changeSplit is a change between values[split-1] and values[split] detected within
+// values[lo:hi].
+type changeSplit struct {
+	lo, split, hi int
+	score         float64
+}
+
+// segment returns the accepted splits of values[lo:hi] in order.
+func segment(values []float64, lo, hi int, opts ChangePointOptions) []changeSplit {
+	if hi-lo < changeSides*opts.MinSegment {
+		return nil
+	}
+
+	best, score := bestSplit(values[lo:hi], opts.MinSegment)
+	split := lo + best
+
+	before, after := mean(values[lo:split]), mean(values[split:hi])
+	if score < opts.Threshold || before == 0 || math.Abs(after-before)/before*percentScale < opts.MinChange {
+		return nil
+	}
+
+	found := segment(values, lo, split, opts)
+	found = append(found, changeSplit{lo: lo, split: split, hi: hi, score: score})
+
+	return append(found, segment(values, split, hi, opts)...)
+}
+
+// bestSplit returns the split of values with the largest two-sample t statistic, using
+// the pooled variance of both sides.
+func bestSplit(values []float64, minSegment int) (int, float64) {
+	n := len(values)
+	prefix := make([]float64, n+1)
+	prefixSq := make([]float64, n+1)
+
+	for i, value := range values {
+		prefix[i+1] = prefix[i] + value
+		prefixSq[i+1] = prefixSq[i] + value*value
+	}
+
+	// squared deviations of values[from:to] around their mean
+	deviation := func(from, to int) float64 {
+		sum := prefix[to] - prefix[from]
+
+		return prefixSq[to] - prefixSq[from] - sum*sum/float64(to-from)
+	}
+
+	best, bestScore := 0, -1.0
+
+	for split := minSegment; split <= n-minSegment; split++ {
+		left, right := float64(split), float64(n-split)
+		diff := (prefix[n]-prefix[split])/right - prefix[split]/left
+
+		// a floor of 1ns² keeps noiseless series finite
+		variance := math.Max((deviation(0, split)+deviation(split, n))/float64(max(n-changeSides, 1)), 1)
+
+		score := math.Abs(diff) / math.Sqrt(variance*(1/left+1/right))
+		if score > bestScore {
+			best, bestScore = split, score
+		}
+	}
+
+	return best, bestScore
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+
+	return sum / float64(len(values))
+}
+
+func newChangePoint(runs []HistoryRecord, values []float64, split changeSplit) ChangePoint {
+	before, after := mean(values[split.lo:split.split]), mean(values[split.split:split.hi])
+	run := runs[split.split]
+
+	return ChangePoint{
+		Name:    run.Name,
+		Version: run.Version,
+		Time:    run.Time,
+		Commit:  run.Commit,
+		Before:  time.Duration(math.Round(before)),
+		After:   time.Duration(math.Round(after)),
+		Percent: (after - before) / before * percentScale,
+		Score:   split.score,
+		Latest:  false,
+	}
+}
+
+// RenderChangePoints writes the change points in the given format: "table" (or empty) for a
+// "Change points" section with a table of the changes, or a note when there are none, and
+// "json" for an indented array. Durations in the table are formatted with durations.
+func RenderChangePoints(w io.Writer, points []ChangePoint, format string, durations DurationFormat) error {
+	switch format {
+	case "", "table":
+		return writeChangePoints(w, points, durations)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		err := enc.Encode(points)
+		if err != nil {
+			return fmt.Errorf("encode change points: %w", err)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+func writeChangePoints(w io.Writer, points []ChangePoint, durations DurationFormat) error {
+	_, err := io.WriteString(w, "### Change points\n\n")
+	if err != nil {
+		return fmt.Errorf("write change points: %w", err)
+	}
+
+	if len(points) == 0 {
+		_, err = io.WriteString(w, "No step changes detected.\n")
+		if err != nil {
+			return fmt.Errorf("write change points: %w", err)
+		}
+
+		return nil
+	}
+
+	columns := []string{"Name", "Version", "Since", "Commit", "Before", "After", "Change", "Score"}
+	rows := make([][]string, 0, len(points))
+
+	for _, point := range points {
+		change := fmt.Sprintf("%+.1f%%", point.Percent)
+		if point.Regression() {
+			change += " slower"
+		}
+
+		rows = append(rows, []string{
+			point.Name, point.Version, point.Time.Format(time.RFC3339), shortCommit(point.Commit),
+			durations.Format(point.Before), durations.Format(point.After), change, fmt.Sprintf("%.1f", point.Score),
+		})
+	}
+
+	return renderTable(w, columns, rows)
+}
+
+func shortCommit(commit string) string {
+	const length = 12
+
+	if len(commit) > length && strings.Trim(commit, "0123456789abcdef") == "" {
+		return commit[:length]
+	}
+
+	return commit
+}
@@ -0,0 +1,219 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Config is a set of named report profiles read from a YAML file.
+type Config struct {
+	Profiles map[string]Profile `yaml:"profiles"`
+
+	// dir is the directory of the config file; relative paths are resolved against it.
+	dir string
+}
+
+// Profile is a named combination of report options. Relative paths are resolved against
+// the directory of the config file.
+type Profile struct {
+	// Inputs are report sources as accepted by Loader.Load; defaults to ./build.
+	Inputs []string `yaml:"inputs"`
+	// Group and Major select how versions are derived from report names.
+	Group bool `yaml:"group"`
+	Major bool `yaml:"major"`
+	// Ticks and Median select the statistic shown in the table.
+	Ticks  bool `yaml:"ticks"`
+	Median bool `yaml:"median"`
+	Rotate bool `yaml:"rotate"`
+	// Outputs are "format" or "format=path" specifications, see ParseOutput. When a
+	// profile lists outputs, they replace the table printed to stdout.
+	Outputs   []string `yaml:"outputs"`
+	Template  string   `yaml:"template"`
+	Timestamp string   `yaml:"timestamp"`
+	Jobs      int      `yaml:"jobs"`
+	KeepGoing bool     `yaml:"keep_going"`
+	// Include and Exclude are "[field:]pattern" filters, see ParseFilter.
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+	// Constraint, ExcludePrerelease, LastVersions and VersionOrder select and order the
+	// versions, see Options.
+	Constraint        string   `yaml:"constraint"`
+	ExcludePrerelease bool     `yaml:"exclude_prerelease"`
+	LastVersions      int      `yaml:"last_versions"`
+	VersionOrder      []string `yaml:"version_order"`
+	// Sort, SortOrder and Top order the units and keep the first ones, see Options.
+	Sort      string `yaml:"sort"`
+	SortOrder string `yaml:"sort_order"`
+	Top       int    `yaml:"top"`
+	// Unit, Precision and AlignRight format the durations, see Options.
+	Unit       string `yaml:"unit"`
+	Precision  string `yaml:"precision"`
+	AlignRight bool   `yaml:"align_right"`
+}
+
+// LoadConfig reads a YAML config file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data), yaml.DisallowUnknownField())
+
+	cfg := &Config{Profiles: nil, dir: filepath.Dir(path)}
+
+	err = decoder.Decode(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Names returns the profile names in sorted order.
+func (c *Config) Names() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Options returns the report options of the named profile.
+func (c *Config) Options(name string) (Options, error) {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return Options{}, fmt.Errorf("%w: %s", ErrUnknownProfile, name)
+	}
+
+	stamp, err := ParseTimestamp(profile.Timestamp)
+	if err != nil {
+		return Options{}, fmt.Errorf("profile %s: %w", name, err)
+	}
+
+	filters, err := ParseFilters(profile.Include, profile.Exclude)
+	if err != nil {
+		return Options{}, fmt.Errorf("profile %s: %w", name, err)
+	}
+
+	inputs := make([]string, 0, len(profile.Inputs))
+	for _, spec := range profile.Inputs {
+		inputs = append(inputs, c.resolveInput(spec))
+	}
+
+	outputs := make([]Output, 0, len(profile.Outputs))
+	for _, spec := range profile.Outputs {
+		output := ParseOutput(spec)
+		if output.Path != "" {
+			output.Path = c.resolve(output.Path)
+		}
+
+		outputs = append(outputs, output)
+	}
+
+	tmpl := ""
+	if profile.Template != "" {
+		tmpl = c.resolve(profile.Template)
+	}
+
+	opts := Options{
+		Directory:         c.resolve("build"),
+		Ticks:             profile.Ticks,
+		Group:             profile.Group,
+		Major:             profile.Major,
+		Median:            profile.Median,
+		Rotate:            profile.Rotate,
+		OutputFormat:      "",
+		OutputFile:        "",
+		Timestamp:         stamp,
+		Template:          tmpl,
+		Outputs:           outputs,
+		Progress:          nil,
+		Jobs:              profile.Jobs,
+		Inputs:            inputs,
+		KeepGoing:         profile.KeepGoing,
+		Filters:           filters,
+		VersionConstraint: profile.Constraint,
+		ExcludePrerelease: profile.ExcludePrerelease,
+		LastVersions:      profile.LastVersions,
+		VersionOrder:      profile.VersionOrder,
+		SortBy:            profile.Sort,
+		SortOrder:         profile.SortOrder,
+		Top:               profile.Top,
+		DurationUnit:      profile.Unit,
+		DurationPrecision: profile.Precision,
+		AlignRight:        profile.AlignRight,
+	}
+
+	err = opts.Validate()
+	if err != nil {
+		return Options{}, fmt.Errorf("profile %s: %w", name, err)
+	}
+
+	return opts, nil
+}
+
+// Run renders the named profile like RunContext. Output meant for stdout goes to w,
+// which is not written to when the profile lists only file outputs.
+func (c *Config) Run(ctx context.Context, w io.Writer, name string, progress ProgressFunc) error {
+	opts, err := c.Options(name)
+	if err != nil {
+		return err
+	}
+
+	opts.Progress = progress
+
+	if !c.printsToStdout(name) {
+		w = io.Discard
+	}
+
+	return RunContext(ctx, w, opts)
+}
+
+// printsToStdout reports whether the named profile prints to stdout, either through the
+// default table or an output without a path.
+func (c *Config) printsToStdout(name string) bool {
+	profile := c.Profiles[name]
+	if len(profile.Outputs) == 0 {
+		return true
+	}
+
+	for _, spec := range profile.Outputs {
+		if ParseOutput(spec).Path == "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Config) resolve(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(c.dir, path)
+}
+
+func (c *Config) resolveInput(spec string) string {
+	source := parseSource(spec)
+	if source.path == stdinSource {
+		return spec
+	}
+
+	if source.label == "" {
+		return c.resolve(source.path)
+	}
+
+	return source.label + "=" + c.resolve(source.path)
+}
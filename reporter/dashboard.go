@@ -0,0 +1,128 @@
+package reporter
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+//go:embed dashboard/index.html
+var dashboardPage []byte
+
+// ErrUnknownVersion is returned when a compared version is not in the report.
+var ErrUnknownVersion = errors.New("unknown version")
+
+// NewDashboard returns a handler serving an interactive dashboard and a JSON API over
+// the reports selected by opts. Reports are loaded again on every API request, so new
+// and changed files show up on reload. The dashboard has no external assets and works
+// offline. With Options.KeepGoing unreadable reports are left out and counted in the
+// X-Skipped-Reports header. Reading stdin fails with ErrWatchStdin, as it can be read only
+// once, and an API request that runs out of time fails with 504 Gateway Timeout. JSON keys
+// are snake_case throughout.
+//
+//	GET /                     the dashboard
+//	GET /api/versions         the sorted versions
+//	GET /api/units            the units with a cell per version, see TemplateUnit
+//	GET /api/trend            the trend of every unit across the versions, see UnitTrend
+//	GET /api/compare?a=&b=    version a compared with version b, see Diff
+func NewDashboard(opts Options) (http.Handler, error) {
+	_, err := rereadableSources(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(dashboardPage)
+	})
+
+	mux.HandleFunc("GET /api/versions", dashboardAPI(opts, func(report *Report, _ *http.Request) (any, error) {
+		return report.Versions, nil
+	}))
+
+	mux.HandleFunc("GET /api/units", dashboardAPI(opts, func(report *Report, _ *http.Request) (any, error) {
+		return buildTemplateData(report).Units, nil
+	}))
+
+	mux.HandleFunc("GET /api/trend", dashboardAPI(opts, func(report *Report, _ *http.Request) (any, error) {
+		return report.Trends(), nil
+	}))
+
+	mux.HandleFunc("GET /api/compare", dashboardAPI(opts, func(report *Report, r *http.Request) (any, error) {
+		oldVer, newVer := r.URL.Query().Get("a"), r.URL.Query().Get("b")
+
+		for _, ver := range []string{oldVer, newVer} {
+			if !slices.Contains(report.Versions, ver) {
+				return nil, fmt.Errorf("%w: %q", ErrUnknownVersion, ver)
+			}
+		}
+
+		return report.DiffVersions(oldVer, newVer), nil
+	}))
+
+	return mux, nil
+}
+
+// dashboardAPI loads the reports, passes the report to build and writes its result as JSON.
+func dashboardAPI(opts Options, build func(report *Report, r *http.Request) (any, error)) http.HandlerFunc {
+	// the progress callback writes to a terminal line that the server does not own
+	opts.Progress = nil
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := LoadOptions(r.Context(), opts)
+		if errors.Is(err, context.DeadlineExceeded) {
+			writeAPIError(w, http.StatusGatewayTimeout, err)
+
+			return
+		}
+
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err)
+
+			return
+		}
+
+		if skipped := data.Errors(); len(skipped) > 0 {
+			w.Header().Set("X-Skipped-Reports", strconv.Itoa(len(skipped)))
+		}
+
+		report := Aggregate(data, opts)
+
+		err = opts.ValidateSort(report.Versions)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err)
+
+			return
+		}
+
+		value, err := build(report, r)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		_ = json.NewEncoder(w).Encode(value)
+	}
+}
+
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	if errors.Is(err, context.Canceled) {
+		// the client went away
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,346 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+const (
+	diffOld = "old"
+	diffNew = "new"
+	// significanceLevel is the p-value below which a change is reported as significant.
+	significanceLevel = 0.05
+	// minSignificanceSamples is the smallest sample per side the significance test runs on.
+	minSignificanceSamples = 3
+	half                   = 0.5
+	// uVarianceDivisor is the denominator of the variance of the U statistic.
+	uVarianceDivisor = 12
+)
+
+// Diff compares two datasets unit by unit.
+type Diff struct {
+	// Stat is the compared statistic, "mean" or "median".
+	Stat string `json:"stat"`
+	// Units are the units present on both sides, sorted by name.
+	Units []DiffUnit `json:"units"`
+	// Added and Removed are units present on one side only.
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	// NewlyFailing units had no failed or errored samples before and have some now;
+	// NewlyPassing units are the other way round.
+	NewlyFailing []string `json:"newly_failing"`
+	NewlyPassing []string `json:"newly_passing"`
+}
+
+// DiffUnit is a unit present in both datasets.
+type DiffUnit struct {
+	Name string    `json:"name"`
+	Old  DiffStats `json:"old"`
+	New  DiffStats `json:"new"`
+	// OK reports whether both sides have passed samples, so the fields below are set.
+	OK    bool          `json:"ok"`
+	Delta time.Duration `json:"delta"`
+	// Percent is the relative change; nil when the old value is zero.
+	Percent *float64 `json:"percent"`
+	// PValue is the two-sided Mann-Whitney U test over passed durations; nil when either
+	// side has fewer than three passed samples.
+	PValue      *float64 `json:"p_value"`
+	Significant bool     `json:"significant"`
+}
+
+// DiffStats are the statistics of a unit on one side of a diff, see UnitStats. Durations
+// are computed over passed samples only.
+type DiffStats struct {
+	Sum     time.Duration `json:"sum"`
+	Mean    time.Duration `json:"mean"`
+	Median  time.Duration `json:"median"`
+	Min     time.Duration `json:"min"`
+	Max     time.Duration `json:"max"`
+	Passed  int           `json:"passed"`
+	Failed  int           `json:"failed"`
+	Skipped int           `json:"skipped"`
+	Errors  int           `json:"errors"`
+}
+
+func newDiffStats(agg UnitStats) DiffStats {
+	return DiffStats{
+		Sum: agg.Sum, Mean: agg.Mean, Median: agg.Median, Min: agg.Min, Max: agg.Max,
+		Passed: agg.Passed, Failed: agg.Failed, Skipped: agg.Skipped, Errors: agg.Errors,
+	}
+}
+
+// DiffDatasets aligns the units of two datasets by name regardless of report names and
+// compares the mean, or the median when median is set, of their passed durations.
+func DiffDatasets(old, cur *Dataset, median bool) *Diff {
+	var files []loadedFile
+
+	for _, side := range []struct {
+		version string
+		data    *Dataset
+	}{{version: diffOld, data: old}, {version: diffNew, data: cur}} {
+		for _, file := range side.data.files {
+			file.Version = side.version
+			files = append(files, file)
+		}
+	}
+
+	opts := defaultOptions()
+	opts.Median = median
+
+	units, _, _ := groupUnits(files, opts)
+
+	return diffVersions(units, diffOld, diffNew, median)
+}
+
+// DiffVersions compares two versions of the report unit by unit, like DiffDatasets, using
+// the mean or, with Options.Median, the median of the passed durations.
+func (r *Report) DiffVersions(oldVer, newVer string) *Diff {
+	return diffVersions(r.units, oldVer, newVer, r.Options.Median)
+}
+
+// diffVersions compares the samples of two versions of the units.
+func diffVersions(units map[string]*unit, oldVer, newVer string, median bool) *Diff {
+	diff := &Diff{Stat: "mean", Units: nil, Added: nil, Removed: nil, NewlyFailing: nil, NewlyPassing: nil}
+	if median {
+		diff.Stat = "median"
+	}
+
+	for _, key := range sortedUnitKeys(units) {
+		unitVal := units[key]
+		oldStats, newStats := unitVal.Aggregate(oldVer), unitVal.Aggregate(newVer)
+
+		switch {
+		case oldStats.Total() == 0 && newStats.Total() == 0:
+			continue
+		case oldStats.Total() == 0:
+			diff.Added = append(diff.Added, key)
+
+			continue
+		case newStats.Total() == 0:
+			diff.Removed = append(diff.Removed, key)
+
+			continue
+		}
+
+		oldFailing, newFailing := oldStats.Failed+oldStats.Errors > 0, newStats.Failed+newStats.Errors > 0
+
+		switch {
+		case !oldFailing && newFailing:
+			diff.NewlyFailing = append(diff.NewlyFailing, key)
+		case oldFailing && !newFailing:
+			diff.NewlyPassing = append(diff.NewlyPassing, key)
+		}
+
+		diff.Units = append(diff.Units, newDiffUnit(unitVal, oldStats, newStats, median))
+	}
+
+	return diff
+}
+
+func newDiffUnit(unitVal *unit, oldStats, newStats UnitStats, median bool) DiffUnit {
+	diffUnit := DiffUnit{
+		Name: oldStats.Name, Old: newDiffStats(oldStats), New: newDiffStats(newStats),
+		OK: false, Delta: 0, Percent: nil, PValue: nil, Significant: false,
+	}
+
+	if !oldStats.HasDurations() || !newStats.HasDurations() {
+		return diffUnit
+	}
+
+	oldValue, newValue := oldStats.Mean, newStats.Mean
+	if median {
+		oldValue, newValue = oldStats.Median, newStats.Median
+	}
+
+	diffUnit.OK = true
+	diffUnit.Delta = newValue - oldValue
+
+	if oldValue != 0 {
+		percent := float64(diffUnit.Delta) / float64(oldValue) * percentScale
+		diffUnit.Percent = &percent
+	}
+
+	oldSamples, newSamples := unitVal.passedDurations(oldStats.Version), unitVal.passedDurations(newStats.Version)
+	if len(oldSamples) >= minSignificanceSamples && len(newSamples) >= minSignificanceSamples {
+		pValue := mannWhitneyU(oldSamples, newSamples)
+		diffUnit.PValue = &pValue
+		diffUnit.Significant = pValue < significanceLevel
+	}
+
+	return diffUnit
+}
+
+func (u *unit) passedDurations(ver string) []time.Duration {
+	var durations []time.Duration
+
+	for _, sample := range u.t {
+		if sample.Ver == ver && sample.JUnit.Status == junit.StatusPassed {
+			durations = append(durations, sample.JUnit.Duration)
+		}
+	}
+
+	return durations
+}
+
+// mannWhitneyU returns the two-sided p-value of the Mann-Whitney U test using the normal
+// approximation with tie and continuity corrections.
+func mannWhitneyU(a, b []time.Duration) float64 {
+	type rankedSample struct {
+		value time.Duration
+		first bool
+	}
+
+	samples := make([]rankedSample, 0, len(a)+len(b))
+	for _, value := range a {
+		samples = append(samples, rankedSample{value: value, first: true})
+	}
+
+	for _, value := range b {
+		samples = append(samples, rankedSample{value: value, first: false})
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i].value < samples[j].value })
+
+	var rankSum, tieTerm float64
+
+	for i := 0; i < len(samples); {
+		j := i
+		for j < len(samples) && samples[j].value == samples[i].value {
+			j++
+		}
+
+		// tied samples share the average of ranks i+1..j
+		rank := float64(i+j+1) * half
+
+		for _, sample := range samples[i:j] {
+			if sample.first {
+				rankSum += rank
+			}
+		}
+
+		ties := float64(j - i)
+		tieTerm += ties*ties*ties - ties
+		i = j
+	}
+
+	n1, n2 := float64(len(a)), float64(len(b))
+	total := n1 + n2
+	u := rankSum - n1*(n1+1)*half
+	mean := n1 * n2 * half
+
+	variance := n1 * n2 / uVarianceDivisor * ((total + 1) - tieTerm/(total*(total-1)))
+	if variance <= 0 {
+		return 1
+	}
+
+	// the continuity correction moves U half a step towards its mean
+	z := math.Max(math.Abs(u-mean)-half, 0) / math.Sqrt(variance)
+
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// RenderDiff writes the diff as a table with lists of changed units, or as JSON. Durations
+// in the table are formatted with durations.
+func RenderDiff(w io.Writer, diff *Diff, format string, durations DurationFormat) error {
+	switch format {
+	case "", "table":
+		return renderDiffTable(w, diff, durations)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		err := enc.Encode(diff)
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// formatPValue prints three decimals, which is enough to compare with significanceLevel.
+func formatPValue(pValue float64) string {
+	const smallest = 0.001
+
+	if pValue < smallest {
+		return "<0.001"
+	}
+
+	return fmt.Sprintf("%.3f", pValue)
+}
+
+func renderDiffTable(w io.Writer, diff *Diff, durations DurationFormat) error {
+	columns := []string{"Name", "Old " + diff.Stat, "New " + diff.Stat, "Delta", "Change", "p", "Significant"}
+	rows := make([][]string, 0, len(diff.Units))
+
+	for _, unitVal := range diff.Units {
+		row := []string{unitVal.Name, ErrDash.Error(), ErrDash.Error(), ErrDash.Error(), ErrDash.Error(), ErrDash.Error(), ""}
+
+		if unitVal.OK {
+			oldValue, newValue := unitVal.Old.Mean, unitVal.New.Mean
+			if diff.Stat == "median" {
+				oldValue, newValue = unitVal.Old.Median, unitVal.New.Median
+			}
+
+			row[1], row[2] = durations.Format(oldValue), durations.Format(newValue)
+			row[3] = durations.FormatSigned(unitVal.Delta)
+		}
+
+		if unitVal.Percent != nil {
+			row[4] = fmt.Sprintf("%+.1f%%", *unitVal.Percent)
+		}
+
+		if unitVal.PValue != nil {
+			row[5] = formatPValue(*unitVal.PValue)
+		}
+
+		if unitVal.Significant {
+			row[6] = "yes"
+		}
+
+		rows = append(rows, row)
+	}
+
+	err := renderTable(w, columns, rows)
+	if err != nil {
+		return err
+	}
+
+	sections := []struct {
+		title string
+		names []string
+	}{
+		{title: "Added", names: diff.Added},
+		{title: "Removed", names: diff.Removed},
+		{title: "Newly failing", names: diff.NewlyFailing},
+		{title: "Newly passing", names: diff.NewlyPassing},
+	}
+
+	for _, section := range sections {
+		if len(section.names) == 0 {
+			continue
+		}
+
+		_, err = fmt.Fprintf(w, "\n%s (%d):\n", section.title, len(section.names))
+		if err != nil {
+			return fmt.Errorf("write diff: %w", err)
+		}
+
+		for _, name := range section.names {
+			_, err = fmt.Fprintf(w, "- %s\n", name)
+			if err != nil {
+				return fmt.Errorf("write diff: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
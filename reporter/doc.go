@@ -0,0 +1,22 @@
+// Package reporter builds benchmark comparison reports from JUnit XML files.
+//
+// A report is produced in three steps: Load reads JUnit reports from files or
+// directories, Aggregate groups the tests into units and versions and computes
+// the table cells, and Render writes the report in one of the Formats:
+//
+//	data, err := reporter.Load(ctx, "./build")
+//	if err != nil {
+//		return err
+//	}
+//
+//	report := reporter.Aggregate(data, opts)
+//
+//	return reporter.Render(os.Stdout, report, "table")
+//
+// Load streams the reports but keeps every test case as a sample for Aggregate,
+// so memory grows linearly with the number of test cases rather than with the
+// size of the files.
+//
+// Report.Stats and Report.Series expose the raw statistics for custom processing,
+// and Run combines all steps the same way the junit-reporter command does.
+package reporter
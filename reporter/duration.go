@@ -0,0 +1,167 @@
+package reporter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDurationFormat is returned by Options.Validate for an unknown duration unit or
+// a malformed precision.
+var ErrInvalidDurationFormat = errors.New("invalid duration format")
+
+const (
+	// defaultDigits is the number of significant digits of automatically scaled durations.
+	defaultDigits = 3
+	decimalBase   = 10
+	// maxPrecision bounds digits and decimals, beyond which nanoseconds are exhausted anyway.
+	maxPrecision = 18
+)
+
+// durationUnit is a unit accepted by Options.DurationUnit.
+type durationUnit struct {
+	name string
+	size time.Duration
+}
+
+// durationUnits returns the units accepted by Options.DurationUnit, smallest first.
+func durationUnits() []durationUnit {
+	return []durationUnit{
+		{name: "ns", size: time.Nanosecond},
+		{name: "µs", size: time.Microsecond},
+		{name: "ms", size: time.Millisecond},
+		{name: "s", size: time.Second},
+	}
+}
+
+// DurationFormat formats the durations of report cells and of the diff, history and changes
+// output, see Options.DurationUnit and Options.DurationPrecision. The zero value scales every
+// duration on its own and keeps three significant digits.
+type DurationFormat struct {
+	unit   string
+	size   time.Duration
+	digits int
+	// decimals is the fixed number of decimals when fixed is set.
+	decimals int
+	fixed    bool
+}
+
+// ParseDurationFormat parses a unit ("", "ns", "us" or "µs", "ms", "s") and a precision:
+// "N" rounds to N significant digits, ".N" prints N decimals and "" keeps three significant
+// digits.
+func ParseDurationFormat(unit, precision string) (DurationFormat, error) {
+	format := DurationFormat{unit: "", size: 0, digits: 0, decimals: 0, fixed: false}
+
+	if unit != "" {
+		name := unit
+		if name == "us" {
+			name = "µs"
+		}
+
+		for _, known := range durationUnits() {
+			if known.name == name {
+				format.unit, format.size = known.name, known.size
+			}
+		}
+
+		if format.size == 0 {
+			return format, fmt.Errorf("%w: unit %q, expected ns, us, ms or s", ErrInvalidDurationFormat, unit)
+		}
+	}
+
+	if precision == "" {
+		return format, nil
+	}
+
+	digits, decimals := strings.CutPrefix(precision, ".")
+
+	value, err := strconv.Atoi(digits)
+	if err != nil || value < 0 || value > maxPrecision || !decimals && value == 0 {
+		return format, fmt.Errorf("%w: precision %q, expected digits such as 4 or decimals such as .2",
+			ErrInvalidDurationFormat, precision)
+	}
+
+	if decimals {
+		format.decimals, format.fixed = value, true
+	} else {
+		format.digits = value
+	}
+
+	return format, nil
+}
+
+// newDurationFormat returns the duration format of the options; an invalid format falls back
+// to the default, see Options.Validate.
+func newDurationFormat(opts Options) DurationFormat {
+	format, err := ParseDurationFormat(opts.DurationUnit, opts.DurationPrecision)
+	if err != nil {
+		return DurationFormat{unit: "", size: 0, digits: 0, decimals: 0, fixed: false}
+	}
+
+	return format
+}
+
+// Format formats a non-negative duration. Without a unit and decimals it rounds like
+// formatDuration; otherwise the value is printed as a number of the unit, the largest one
+// below the duration when no unit is set.
+func (f DurationFormat) Format(dur time.Duration) string {
+	digits := f.digits
+	if digits == 0 {
+		digits = defaultDigits
+	}
+
+	if f.size == 0 && !f.fixed {
+		return roundDuration(dur, digits).String()
+	}
+
+	unit, size := f.unit, f.size
+	if size == 0 {
+		for _, known := range durationUnits() {
+			if dur >= known.size || known.size == time.Nanosecond {
+				unit, size = known.name, known.size
+			}
+		}
+	}
+
+	value := float64(dur) / float64(size)
+
+	decimals := f.decimals
+	if !f.fixed {
+		decimals = 0
+		if value != 0 {
+			decimals = max(digits-1-int(math.Floor(math.Log10(value))), 0)
+		}
+	}
+
+	return strconv.FormatFloat(value, 'f', decimals, 64) + unit
+}
+
+// FormatSigned formats negative durations too, with an explicit sign for non-zero ones.
+func (f DurationFormat) FormatSigned(dur time.Duration) string {
+	switch {
+	case dur < 0:
+		return "-" + f.Format(-dur)
+	case dur > 0:
+		return "+" + f.Format(dur)
+	default:
+		return f.Format(0)
+	}
+}
+
+// roundDuration rounds a duration to the given number of significant digits; durations above
+// 100 seconds are rounded like 100 seconds, e.g. to whole seconds with three digits.
+func roundDuration(dur time.Duration, digits int) time.Duration {
+	scale := roundBase * time.Second
+	for scale > dur && scale > 1 {
+		scale /= decimalBase
+	}
+
+	for range digits - 1 {
+		scale /= decimalBase
+	}
+
+	return dur.Round(max(scale, 1))
+}
@@ -0,0 +1,77 @@
+package reporter_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/bavix/junit-reporter/reporter"
+)
+
+func exampleOptions() reporter.Options {
+	var opts reporter.Options
+
+	opts.Directory = "../build"
+	opts.Ticks, opts.Group, opts.Major, opts.Median = true, true, true, true
+
+	return opts
+}
+
+func ExampleLoad() {
+	data, err := reporter.Load(context.Background(), "../build/junit-7.0.0.xml", "../build/junit-7.1.0.xml")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(data.Files())
+	// Output: [../build/junit-7.0.0.xml ../build/junit-7.1.0.xml]
+}
+
+func ExampleAggregate() {
+	data, err := reporter.Load(context.Background(), "../build")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	report := reporter.Aggregate(data, exampleOptions())
+
+	fmt.Println(report.Columns)
+
+	for _, stat := range report.Stats() {
+		if stat.Name == "Cart:Pay" {
+			fmt.Println(stat.Version, stat.Passed, stat.Median)
+		}
+	}
+	// Output:
+	// [Name 6.x 7.x 10.x]
+	// 6.x 100 1.1539465s
+	// 7.x 100 1.011073s
+	// 10.x 25 1.071188s
+}
+
+func ExampleRender() {
+	data, err := reporter.Load(context.Background(), "../build/junit-7.0.0.xml", "../build/junit-7.1.0.xml")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := exampleOptions()
+	opts.Group, opts.Major = false, false
+
+	opts.Filters, err = reporter.ParseFilters([]string{"class:Cart"}, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = reporter.Render(os.Stdout, reporter.Aggregate(data, opts), "csv")
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Output:
+	// Name,7.0.0,7.1.0
+	// Cart:EagerLoaderPay,8.47s,10.4s
+	// Cart:Pay,600ms,660ms
+	// Cart:PayFree,427ms,423ms
+	// Cart:PayOneItemXPieces,190ms,299ms
+}
@@ -0,0 +1,152 @@
+package reporter
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/joshdk/go-junit"
+)
+
+// ErrInvalidFilter is returned for a filter with an unknown field or a malformed pattern.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// FilterField is the part of a test a Filter matches.
+type FilterField string
+
+const (
+	// FilterName matches the unit name as shown in the table, e.g. "Cart:Pay".
+	FilterName FilterField = "name"
+	// FilterClass matches the class, with or without its "Test" suffix.
+	FilterClass FilterField = "class"
+	// FilterMethod matches the method, with or without its "test" prefix.
+	FilterMethod FilterField = "method"
+	// FilterNamespace matches the classname without the class, e.g. "com.acme.cart".
+	FilterNamespace FilterField = "namespace"
+)
+
+// Filter selects the tests that make up the report. Include filters keep only the tests
+// that match at least one of them; exclude filters drop the tests that match any of them.
+// Filters are created with ParseFilter.
+type Filter struct {
+	Field   FilterField
+	Pattern string
+	Exclude bool
+
+	match func(value string) bool
+}
+
+// ParseFilter parses a "[field:]pattern" filter. The field is one of name (the default),
+// class, method and namespace. The pattern is a glob as in path.Match, or a regular
+// expression between slashes such as "/^Cart:Pay(Free)?$/".
+func ParseFilter(spec string, exclude bool) (Filter, error) {
+	filter := Filter{Field: FilterName, Pattern: spec, Exclude: exclude, match: nil}
+
+	if field, pattern, ok := strings.Cut(spec, ":"); ok {
+		switch FilterField(field) {
+		case FilterName, FilterClass, FilterMethod, FilterNamespace:
+			filter.Field, filter.Pattern = FilterField(field), pattern
+		}
+	}
+
+	if len(filter.Pattern) > 1 && strings.HasPrefix(filter.Pattern, "/") && strings.HasSuffix(filter.Pattern, "/") {
+		re, err := regexp.Compile(filter.Pattern[1 : len(filter.Pattern)-1])
+		if err != nil {
+			return filter, fmt.Errorf("%w %q: %w", ErrInvalidFilter, spec, err)
+		}
+
+		filter.match = re.MatchString
+
+		return filter, nil
+	}
+
+	_, err := path.Match(filter.Pattern, "")
+	if err != nil {
+		return filter, fmt.Errorf("%w %q: %w", ErrInvalidFilter, spec, err)
+	}
+
+	filter.match = func(value string) bool {
+		matched, _ := path.Match(filter.Pattern, value)
+
+		return matched
+	}
+
+	return filter, nil
+}
+
+// ParseFilters parses include and exclude filters, see ParseFilter.
+func ParseFilters(include, exclude []string) ([]Filter, error) {
+	filters := make([]Filter, 0, len(include)+len(exclude))
+
+	for _, side := range []struct {
+		specs   []string
+		exclude bool
+	}{{specs: include, exclude: false}, {specs: exclude, exclude: true}} {
+		for _, spec := range side.specs {
+			filter, err := ParseFilter(spec, side.exclude)
+			if err != nil {
+				return nil, err
+			}
+
+			filters = append(filters, filter)
+		}
+	}
+
+	return filters, nil
+}
+
+// String returns the filter in the form accepted by ParseFilter.
+func (f Filter) String() string {
+	return string(f.Field) + ":" + f.Pattern
+}
+
+// matches reports whether the field of the test matches the pattern.
+func (f Filter) matches(test junit.Test) bool {
+	namespace, class := "", test.Classname
+	if idx := strings.LastIndex(test.Classname, "."); idx >= 0 {
+		namespace, class = test.Classname[:idx], test.Classname[idx+1:]
+	}
+
+	var method string
+	if fields := strings.Fields(test.Name); len(fields) > 0 {
+		method = fields[0]
+	}
+
+	var values []string
+
+	switch f.Field {
+	case FilterClass:
+		values = []string{class, strings.TrimSuffix(class, "Test")}
+	case FilterMethod:
+		values = []string{method, strings.TrimPrefix(method, "test")}
+	case FilterNamespace:
+		values = []string{namespace}
+	default:
+		values = []string{strings.TrimSuffix(class, "Test") + ":" + strings.TrimPrefix(method, "test")}
+	}
+
+	return slices.ContainsFunc(values, f.match)
+}
+
+// keepTest applies the filters to a test.
+func keepTest(filters []Filter, test junit.Test) bool {
+	included, hasInclude := false, false
+
+	for _, filter := range filters {
+		if filter.Exclude {
+			if filter.matches(test) {
+				return false
+			}
+
+			continue
+		}
+
+		hasInclude = true
+		included = included || filter.matches(test)
+	}
+
+	return included || !hasInclude
+}
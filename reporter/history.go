@@ -0,0 +1,253 @@
+package reporter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	historyFile     = "history.jsonl"
+	historyDirPerm  = 0o755
+	historyFilePerm = 0o644
+	// historyLineLimit bounds a single history line; records are a few hundred bytes.
+	historyLineLimit = 1 << 20
+)
+
+// HistoryRecord is the aggregate of a unit and version in a recorded run. A run is
+// identified by its Time; all records of one Record call share it.
+type HistoryRecord struct {
+	Time    time.Time     `json:"time"`
+	Commit  string        `json:"commit,omitempty"`
+	Version string        `json:"version"`
+	Name    string        `json:"name"`
+	Sum     time.Duration `json:"sum"`
+	Mean    time.Duration `json:"mean"`
+	Median  time.Duration `json:"median"`
+	Min     time.Duration `json:"min"`
+	Max     time.Duration `json:"max"`
+	Passed  int           `json:"passed"`
+	Failed  int           `json:"failed"`
+	Skipped int           `json:"skipped"`
+	Errors  int           `json:"errors"`
+}
+
+// HistoryQuery selects history records. Empty fields match everything.
+type HistoryQuery struct {
+	// Name is a unit name or a path.Match pattern such as "Cart:*".
+	Name    string
+	Version string
+	Commit  string
+	Since   time.Time
+	Until   time.Time
+	// Last keeps only the records of the last N matching runs.
+	Last int
+}
+
+// History is an append-only store of run aggregates kept as JSON lines in a directory.
+type History struct {
+	dir string
+}
+
+// OpenHistory returns the store in dir; the directory is created on the first Record.
+func OpenHistory(dir string) *History {
+	return &History{dir: dir}
+}
+
+// Path returns the file the records are appended to.
+func (h *History) Path() string {
+	return filepath.Join(h.dir, historyFile)
+}
+
+// Record appends the statistics of every unit and version of the report as a run at the
+// given time and returns the number of records written.
+func (h *History) Record(report *Report, commit string, at time.Time) (int, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	stats := report.Stats()
+
+	for _, agg := range stats {
+		err := enc.Encode(HistoryRecord{
+			Time:    at.UTC(),
+			Commit:  commit,
+			Version: agg.Version,
+			Name:    agg.Name,
+			Sum:     agg.Sum,
+			Mean:    agg.Mean,
+			Median:  agg.Median,
+			Min:     agg.Min,
+			Max:     agg.Max,
+			Passed:  agg.Passed,
+			Failed:  agg.Failed,
+			Skipped: agg.Skipped,
+			Errors:  agg.Errors,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("encode history record: %w", err)
+		}
+	}
+
+	err := os.MkdirAll(h.dir, historyDirPerm)
+	if err != nil {
+		return 0, fmt.Errorf("create history directory: %w", err)
+	}
+
+	f, err := os.OpenFile(h.Path(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, historyFilePerm)
+	if err != nil {
+		return 0, fmt.Errorf("open history: %w", err)
+	}
+
+	// a single write keeps the records of a run together when runs are recorded concurrently
+	_, err = f.Write(buf.Bytes())
+	if err != nil {
+		_ = f.Close()
+
+		return 0, fmt.Errorf("append history: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return 0, fmt.Errorf("close history: %w", err)
+	}
+
+	return len(stats), nil
+}
+
+// Query returns the matching records ordered by run time, in recording order within a run.
+// A store that was never written to is empty.
+func (h *History) Query(query HistoryQuery) ([]HistoryRecord, error) {
+	f, err := os.Open(h.Path())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("open history: %w", err)
+	}
+	defer f.Close()
+
+	var records []HistoryRecord
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, historyLineLimit)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var record HistoryRecord
+
+		err = json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			return nil, fmt.Errorf("parse history %s:%d: %w", h.Path(), line, err)
+		}
+
+		if query.matches(record) {
+			records = append(records, record)
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+
+	slices.SortStableFunc(records, func(a, b HistoryRecord) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return lastRuns(records, query.Last), nil
+}
+
+func (q HistoryQuery) matches(record HistoryRecord) bool {
+	if q.Name != "" && q.Name != record.Name {
+		matched, err := path.Match(q.Name, record.Name)
+		if err != nil || !matched {
+			return false
+		}
+	}
+
+	switch {
+	case q.Version != "" && q.Version != record.Version,
+		q.Commit != "" && q.Commit != record.Commit,
+		!q.Since.IsZero() && record.Time.Before(q.Since),
+		!q.Until.IsZero() && record.Time.After(q.Until):
+		return false
+	default:
+		return true
+	}
+}
+
+// lastRuns keeps the records of the last n runs of records sorted by time.
+func lastRuns(records []HistoryRecord, n int) []HistoryRecord {
+	if n <= 0 {
+		return records
+	}
+
+	runs := 0
+
+	for i := len(records) - 1; i >= 0; i-- {
+		if i == len(records)-1 || !records[i].Time.Equal(records[i+1].Time) {
+			runs++
+		}
+
+		if runs > n {
+			return records[i+1:]
+		}
+	}
+
+	return records
+}
+
+// RenderHistory writes history records as a table, JSON lines or CSV. Durations in the table
+// and CSV are formatted with durations.
+func RenderHistory(w io.Writer, records []HistoryRecord, format string, durations DurationFormat) error {
+	switch format {
+	case "", "table", "csv":
+		columns := []string{"Time", "Commit", "Version", "Name", "Mean", "Median", "Passed", "Failed", "Skipped", "Errors"}
+		rows := make([][]string, 0, len(records))
+
+		for _, record := range records {
+			mean, median := ErrDash.Error(), ErrDash.Error()
+			if record.Passed > 0 {
+				mean, median = durations.Format(record.Mean), durations.Format(record.Median)
+			}
+
+			rows = append(rows, []string{
+				record.Time.Format(time.RFC3339), record.Commit, record.Version, record.Name, mean, median,
+				strconv.Itoa(record.Passed), strconv.Itoa(record.Failed), strconv.Itoa(record.Skipped), strconv.Itoa(record.Errors),
+			})
+		}
+
+		if format == "csv" {
+			return writeCSV(w, columns, rows)
+		}
+
+		return renderTable(w, columns, rows)
+	case "jsonl":
+		enc := json.NewEncoder(w)
+
+		for _, record := range records {
+			err := enc.Encode(record)
+			if err != nil {
+				return fmt.Errorf("encode history record: %w", err)
+			}
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
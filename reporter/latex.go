@@ -0,0 +1,81 @@
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// newLatexEscaper escapes characters that have a special meaning in LaTeX.
+func newLatexEscaper() *strings.Replacer {
+	return strings.NewReplacer(
+		`\`, `\textbackslash{}`,
+		`&`, `\&`,
+		`%`, `\%`,
+		`$`, `\$`,
+		`#`, `\#`,
+		`_`, `\_`,
+		`{`, `\{`,
+		`}`, `\}`,
+		`~`, `\textasciitilde{}`,
+		`^`, `\textasciicircum{}`,
+		`µ`, `\textmu{}`,
+	)
+}
+
+// bestCell returns the index of the fastest duration in the row or -1 when the row has none.
+func bestCell(cells []Cell) int {
+	best := -1
+
+	for i, cell := range cells {
+		if cell.OK() && (best < 0 || cell.Value < cells[best].Value) {
+			best = i
+		}
+	}
+
+	return best
+}
+
+// writeLatex writes a booktabs tabular with the fastest value of every row in bold.
+// The document needs \usepackage{booktabs}.
+func writeLatex(w io.Writer, columns []string, grid [][]Cell) error {
+	bw := bufio.NewWriter(w)
+	esc := newLatexEscaper()
+
+	escaped := make([]string, 0, len(columns))
+	for _, column := range columns {
+		escaped = append(escaped, esc.Replace(column))
+	}
+
+	fmt.Fprintf(bw, "\\begin{tabular}{l%s}\n", strings.Repeat("r", max(len(columns)-1, 0)))
+	fmt.Fprintln(bw, `\toprule`)
+	fmt.Fprintf(bw, "%s \\\\\n", strings.Join(escaped, " & "))
+	fmt.Fprintln(bw, `\midrule`)
+
+	for _, cells := range grid {
+		best := bestCell(cells)
+		values := make([]string, 0, len(cells))
+
+		for i, cell := range cells {
+			text := esc.Replace(cell.Text)
+			if i == best {
+				text = `\textbf{` + text + `}`
+			}
+
+			values = append(values, text)
+		}
+
+		fmt.Fprintf(bw, "%s \\\\\n", strings.Join(values, " & "))
+	}
+
+	fmt.Fprintln(bw, `\bottomrule`)
+	fmt.Fprintln(bw, `\end{tabular}`)
+
+	err := bw.Flush()
+	if err != nil {
+		return fmt.Errorf("write latex: %w", err)
+	}
+
+	return nil
+}
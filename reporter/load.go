@@ -0,0 +1,430 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+// loadedFile is a parsed JUnit report with its tests flattened. Only the test fields used
+// for aggregation are kept, see decodeReport.
+type loadedFile struct {
+	Path string
+	// Name is the path the version is parsed from: Path itself, or the entry path for
+	// reports read from an archive.
+	Name  string
+	Label string
+	// Version is the label given explicitly with the source; it overrides the version
+	// parsed from Name.
+	Version string
+	Time    time.Time
+	Tests   []junit.Test
+}
+
+// version returns the version the report is grouped under.
+func (f loadedFile) version(group, major bool) string {
+	if f.Version == "" {
+		return ParseVersionFromPath(f.Name, group, major)
+	}
+
+	if group && versionRE.MatchString(f.Version) {
+		return ParseVersionFromPath(f.Version, true, major)
+	}
+
+	return f.Version
+}
+
+// Dataset holds the tests of loaded JUnit reports. Versions are assigned by Aggregate,
+// so the same dataset can be grouped with different options.
+type Dataset struct {
+	files   []loadedFile
+	skipped []*ReportError
+}
+
+// Errors returns the reports skipped by a Loader with KeepGoing set, in source order.
+func (d *Dataset) Errors() []*ReportError {
+	return d.skipped
+}
+
+// ReportError is a report that could not be read. Line and Column locate XML syntax
+// errors and are zero for other failures.
+type ReportError struct {
+	Path   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *ReportError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d:%d: %v", e.Path, e.Line, e.Column, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *ReportError) Unwrap() error {
+	return e.Err
+}
+
+// newReportError attributes err to the report at path, keeping the position of a
+// ReportError returned by decodeReport.
+func newReportError(path string, err error) *ReportError {
+	var reportErr *ReportError
+	if errors.As(err, &reportErr) {
+		return &ReportError{Path: path, Line: reportErr.Line, Column: reportErr.Column, Err: reportErr.Err}
+	}
+
+	return &ReportError{Path: path, Line: 0, Column: 0, Err: err}
+}
+
+// Files returns the paths of the loaded reports in load order.
+func (d *Dataset) Files() []string {
+	paths := make([]string, 0, len(d.files))
+	for _, file := range d.files {
+		paths = append(paths, file.Path)
+	}
+
+	return paths
+}
+
+// Progress describes how far loading got.
+type Progress struct {
+	// Path is the report parsed last.
+	Path       string
+	Files      int
+	TotalFiles int
+	// Tests is the number of test cases ingested so far.
+	Tests int
+}
+
+// ProgressFunc is called after every parsed report.
+type ProgressFunc func(Progress)
+
+// Loader reads JUnit reports. The zero value parses with GOMAXPROCS workers and
+// reports no progress.
+type Loader struct {
+	// Jobs bounds the number of reports parsed concurrently; values below one mean GOMAXPROCS.
+	Jobs int
+	// Progress, when set, is called after every parsed report, never concurrently.
+	Progress ProgressFunc
+	// Stdin is read by the "-" source; nil means os.Stdin.
+	Stdin io.Reader
+	// KeepGoing skips reports that cannot be read instead of failing; they are listed by
+	// Dataset.Errors.
+	KeepGoing bool
+	// Cache, when set, keeps parsed reports between loads, see ReportCache.
+	Cache *ReportCache
+}
+
+// stdinSource is the source name that reads a single report from Loader.Stdin.
+const stdinSource = "-"
+
+// input is a resolved report file, archive or stdin with its explicit label.
+type input struct {
+	path  string
+	label string
+}
+
+// parseSource splits a "label=path" source. The text before the first "=" is a label
+// only when it has no path separator, so paths containing "=" can still be given as is.
+func parseSource(spec string) input {
+	label, sourcePath, ok := strings.Cut(spec, "=")
+	if !ok || label == "" || strings.ContainsAny(label, `/\`) {
+		return input{path: spec, label: ""}
+	}
+
+	return input{path: sourcePath, label: label}
+}
+
+// Load reads JUnit reports from the given sources. A source is either a report file, an
+// archive or a directory, in which case its junit-*.xml and junit-*.xml.gz files and its
+// .zip, .tar.gz and .tgz archives are read. Every junit-*.xml(.gz) entry of an archive is a
+// separate report. The version of a report is taken from its junit-<version>.xml file name,
+// or from the entry path for archived reports.
+func Load(ctx context.Context, sources ...string) (*Dataset, error) {
+	return Loader{Jobs: 0, Progress: nil, Stdin: nil, KeepGoing: false, Cache: nil}.Load(ctx, sources...)
+}
+
+// LoadProgress is Load reporting progress to the callback, which may be nil.
+func LoadProgress(ctx context.Context, progress ProgressFunc, sources ...string) (*Dataset, error) {
+	return Loader{Jobs: 0, Progress: progress, Stdin: nil, KeepGoing: false, Cache: nil}.Load(ctx, sources...)
+}
+
+// LoadOptions loads opts.Inputs, or opts.Directory when there are none, with the loading
+// options of opts, like RunContext does.
+func LoadOptions(ctx context.Context, opts Options) (*Dataset, error) {
+	sources := opts.Inputs
+	if len(sources) == 0 {
+		sources = []string{opts.Directory}
+	}
+
+	return Loader{Jobs: opts.Jobs, Progress: opts.Progress, Stdin: nil, KeepGoing: opts.KeepGoing, Cache: nil}.Load(ctx, sources...)
+}
+
+// Load reads the sources like the package-level Load. A source may also be "-", a single
+// report read from Stdin, and any source may be given as "label=path" to use label as the
+// version of its reports instead of the one in their names; "-" requires a label.
+// Reports are parsed concurrently, but the dataset keeps the order of the sources, so
+// the result does not depend on Jobs. Loading stops with the context error once ctx is done.
+func (l Loader) Load(ctx context.Context, sources ...string) (*Dataset, error) {
+	inputs, err := resolveSources(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.Cache != nil {
+		l.Cache.retain(inputs)
+	}
+
+	return l.parseFiles(ctx, inputs)
+}
+
+func resolveSources(sources []string) ([]input, error) {
+	var inputs []input
+
+	for _, spec := range sources {
+		source := parseSource(spec)
+
+		if source.path == stdinSource {
+			if source.label == "" {
+				return nil, ErrMissingLabel
+			}
+
+			inputs = append(inputs, source)
+
+			continue
+		}
+
+		info, err := os.Stat(source.path)
+		if err == nil && !info.IsDir() {
+			inputs = append(inputs, source)
+
+			continue
+		}
+
+		found, err := discoverJUnitFiles(source.path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, filename := range found {
+			inputs = append(inputs, input{path: filename, label: source.label})
+		}
+	}
+
+	return inputs, nil
+}
+
+// loadFunc parses the reports of a path, see loadPath.
+type loadFunc func(path string) ([]loadedFile, []*ReportError, error)
+
+// loadInput parses an input and applies its explicit label to the reports. The archive
+// entries skipped with KeepGoing are returned along with them.
+func (l Loader) loadInput(ctx context.Context, in input) ([]loadedFile, []*ReportError, error) {
+	var (
+		files   []loadedFile
+		skipped []*ReportError
+		err     error
+	)
+
+	if in.path == stdinSource {
+		stdin := l.Stdin
+		if stdin == nil {
+			stdin = os.Stdin
+		}
+
+		var file loadedFile
+
+		file, err = parseEntry(ctx, stdin, stdinSource, stdinSource)
+		files = []loadedFile{file}
+	} else if l.Cache != nil {
+		files, skipped, err = l.Cache.load(in.path, func(path string) ([]loadedFile, []*ReportError, error) {
+			return loadPath(ctx, path, l.KeepGoing)
+		})
+	} else {
+		files, skipped, err = loadPath(ctx, in.path, l.KeepGoing)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if in.label != "" {
+		for i := range files {
+			files[i].Label = in.label
+			files[i].Version = in.label
+		}
+	}
+
+	return files, skipped, nil
+}
+
+type parseResult struct {
+	idx     int
+	files   []loadedFile
+	skipped []*ReportError
+	err     error
+}
+
+// parseFiles parses the files with a bounded worker pool and merges the results by index;
+// an archive expands to its reports in place.
+// When several files fail, the error of the first one in order is returned, unless
+// KeepGoing is set.
+func (l Loader) parseFiles(ctx context.Context, inputs []input) (*Dataset, error) {
+	jobs := l.Jobs
+	if jobs < 1 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+
+	jobs = max(min(jobs, len(inputs)), 1)
+
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	indexes := make(chan int)
+	results := make(chan parseResult)
+
+	go func() {
+		defer close(indexes)
+
+		for idx := range inputs {
+			select {
+			case indexes <- idx:
+			case <-workCtx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+
+	for range jobs {
+		wg.Go(func() {
+			for idx := range indexes {
+				files, skipped, err := l.loadInput(workCtx, inputs[idx])
+				results <- parseResult{idx: idx, files: files, skipped: skipped, err: err}
+			}
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	parsed := make([][]loadedFile, len(inputs))
+	errs := make([][]*ReportError, len(inputs))
+	state := Progress{Path: "", Files: 0, TotalFiles: len(inputs), Tests: 0}
+
+	for res := range results {
+		errs[res.idx] = res.skipped
+
+		// reports stopped by the cancel below would hide the error that caused it
+		if res.err != nil && errors.Is(res.err, context.Canceled) && workCtx.Err() != nil && ctx.Err() == nil {
+			continue
+		}
+
+		if res.err != nil {
+			errs[res.idx] = []*ReportError{newReportError(inputs[res.idx].path, res.err)}
+
+			if !l.KeepGoing {
+				cancel()
+			}
+		}
+
+		parsed[res.idx] = res.files
+
+		if l.Progress != nil {
+			state.Path = inputs[res.idx].path
+			state.Files++
+
+			for _, file := range res.files {
+				state.Tests += len(file.Tests)
+			}
+
+			l.Progress(state)
+		}
+	}
+
+	data := &Dataset{files: nil, skipped: nil}
+
+	for _, group := range errs {
+		for _, err := range group {
+			if !l.KeepGoing {
+				return nil, err
+			}
+
+			data.skipped = append(data.skipped, err)
+		}
+	}
+
+	err := ctx.Err()
+	if err != nil {
+		return nil, fmt.Errorf("load reports: %w", err)
+	}
+
+	for _, group := range parsed {
+		data.files = append(data.files, group...)
+	}
+
+	return data, nil
+}
+
+func loadFile(ctx context.Context, filePath string) (loadedFile, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return loadedFile{}, newReportError(filePath, err)
+	}
+	defer f.Close()
+
+	return parseEntry(ctx, f, filePath, filePath)
+}
+
+// parseReport streams a JUnit document with decodeReport, keeping only the tests. Streaming
+// bounds the memory spent on outputs, not on tests: every test case is kept as a sample until
+// Aggregate groups them, as medians and Mann-Whitney U need all samples of a unit, so memory
+// grows linearly with the number of test cases of the loaded reports.
+func parseReport(ctx context.Context, r io.Reader, filePath, name string) (loadedFile, error) {
+	file := loadedFile{
+		Path:    filePath,
+		Name:    name,
+		Label:   ParseVersionFromPath(name, false, false),
+		Version: "",
+		Time:    time.Time{},
+		Tests:   nil,
+	}
+
+	stamp, err := decodeReport(ctx, r, func(test junit.Test) {
+		file.Tests = append(file.Tests, test)
+	})
+	if err != nil {
+		return loadedFile{}, newReportError(filePath, err)
+	}
+
+	file.Time = stamp
+
+	return file, nil
+}
+
+// Aggregate groups the dataset into units and versions according to opts and builds the report.
+// Versions are sorted by Options.VersionOrder and then semantically with CompareVersions, and
+// only the versions selected by the options are kept.
+func Aggregate(data *Dataset, opts Options) *Report {
+	units, versions, filtered := groupUnits(selectFiles(data.files, opts), opts)
+
+	sortVersions(versions, opts.VersionOrder)
+
+	report := buildReport(units, versions, data.Files(), opts)
+	report.Filtered = filtered
+
+	return report
+}
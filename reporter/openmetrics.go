@@ -0,0 +1,122 @@
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	metricDuration = "junit_test_duration_seconds"
+	metricResults  = "junit_test_results"
+)
+
+// newLabelEscaper escapes label values according to the exposition format.
+func newLabelEscaper() *strings.Replacer {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+}
+
+func openMetricsLabels(esc *strings.Replacer, agg UnitStats, extra ...string) string {
+	pairs := append([]string{"class", agg.Class, "method", agg.Method, "version", agg.Version}, extra...)
+
+	var sb strings.Builder
+
+	sb.WriteByte('{')
+
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+
+		sb.WriteString(pairs[i])
+		sb.WriteString(`="`)
+		sb.WriteString(esc.Replace(pairs[i+1]))
+		sb.WriteByte('"')
+	}
+
+	sb.WriteByte('}')
+
+	return sb.String()
+}
+
+// formatSeconds renders the duration as decimal seconds without float rounding artifacts.
+func formatSeconds(dur time.Duration) string {
+	sign := ""
+	if dur < 0 {
+		sign = "-"
+		dur = -dur
+	}
+
+	secs := int64(dur / time.Second)
+	frac := strings.TrimRight(fmt.Sprintf("%09d", int64(dur%time.Second)), "0")
+
+	if frac == "" {
+		return sign + strconv.FormatInt(secs, 10)
+	}
+
+	return sign + strconv.FormatInt(secs, 10) + "." + frac
+}
+
+// writeOpenMetrics writes duration statistics and result counts as gauges in the
+// OpenMetrics text format. The counts describe the loaded reports rather than a running
+// total, so they are not counters.
+func writeOpenMetrics(w io.Writer, aggs []UnitStats) error {
+	bw := bufio.NewWriter(w)
+	esc := newLabelEscaper()
+
+	fmt.Fprintf(bw, "# TYPE %s gauge\n", metricDuration)
+	fmt.Fprintf(bw, "# UNIT %s seconds\n", metricDuration)
+	fmt.Fprintf(bw, "# HELP %s Duration of passed test samples per version.\n", metricDuration)
+
+	for _, agg := range aggs {
+		if !agg.HasDurations() {
+			continue
+		}
+
+		stats := []struct {
+			name string
+			dur  time.Duration
+		}{
+			{"sum", agg.Sum},
+			{"mean", agg.Mean},
+			{"median", agg.Median},
+			{"min", agg.Min},
+			{"max", agg.Max},
+		}
+
+		for _, stat := range stats {
+			fmt.Fprintf(bw, "%s%s %s\n", metricDuration, openMetricsLabels(esc, agg, "stat", stat.name), formatSeconds(stat.dur))
+		}
+	}
+
+	fmt.Fprintf(bw, "# TYPE %s gauge\n", metricResults)
+	fmt.Fprintf(bw, "# HELP %s Number of test samples per version and status.\n", metricResults)
+
+	for _, agg := range aggs {
+		counts := []struct {
+			status string
+			count  int
+		}{
+			{"passed", agg.Passed},
+			{"failed", agg.Failed},
+			{"skipped", agg.Skipped},
+			{"error", agg.Errors},
+		}
+
+		for _, count := range counts {
+			fmt.Fprintf(bw, "%s%s %d\n", metricResults, openMetricsLabels(esc, agg, "status", count.status), count.count)
+		}
+	}
+
+	fmt.Fprintln(bw, "# EOF")
+
+	err := bw.Flush()
+	if err != nil {
+		return fmt.Errorf("write openmetrics: %w", err)
+	}
+
+	return nil
+}
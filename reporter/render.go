@@ -0,0 +1,223 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// outputDirPerm is the mode of the directories created for output files.
+const outputDirPerm = 0o755
+
+// Renderer writes a report in a single output format.
+type Renderer interface {
+	Render(w io.Writer, report *Report) error
+}
+
+// RendererFunc adapts a plain function to the Renderer interface.
+type RendererFunc func(w io.Writer, report *Report) error
+
+// Render calls f(w, report).
+func (f RendererFunc) Render(w io.Writer, report *Report) error {
+	return f(w, report)
+}
+
+// Format is a named renderer with the extension used for its default file name.
+type Format struct {
+	Name      string
+	Extension string
+	Renderer  Renderer
+}
+
+// Output is a destination of a rendered report. An empty Path writes to the writer given to Run.
+type Output struct {
+	Format string
+	Path   string
+}
+
+// ParseOutput parses a "format" or "format=path" output specification.
+func ParseOutput(spec string) Output {
+	format, outPath, _ := strings.Cut(spec, "=")
+
+	return Output{Format: format, Path: outPath}
+}
+
+// Formats returns the built-in formats by name.
+func Formats() map[string]Format {
+	formats := []Format{
+		{Name: "table", Extension: "md", Renderer: RendererFunc(writeReportTable)},
+		{Name: "csv", Extension: "csv", Renderer: RendererFunc(func(w io.Writer, r *Report) error {
+			return writeCSV(w, r.Columns, r.Texts())
+		})},
+		{Name: "json", Extension: "json", Renderer: RendererFunc(func(w io.Writer, r *Report) error {
+			return writeJSON(w, r.Columns, r.Texts())
+		})},
+		{Name: "openmetrics", Extension: "prom", Renderer: RendererFunc(func(w io.Writer, r *Report) error {
+			return writeOpenMetrics(w, r.Stats())
+		})},
+		{Name: "influx", Extension: "lp", Renderer: RendererFunc(func(w io.Writer, r *Report) error {
+			return writeInflux(w, r.Series(), r.Options.Timestamp)
+		})},
+		{Name: "jsonl", Extension: "jsonl", Renderer: RendererFunc(func(w io.Writer, r *Report) error {
+			return writeSeriesJSONL(w, r.Series(), r.Options.Timestamp)
+		})},
+		{Name: "xlsx", Extension: "xlsx", Renderer: RendererFunc(func(w io.Writer, r *Report) error {
+			return writeXLSX(w, r.units, r.order, r.Versions, r.Options.Rotate)
+		})},
+		{Name: "latex", Extension: "tex", Renderer: RendererFunc(func(w io.Writer, r *Report) error {
+			return writeLatex(w, r.Columns, r.Rows)
+		})},
+		{Name: "rst", Extension: "rst", Renderer: RendererFunc(func(w io.Writer, r *Report) error {
+			return writeRSTGrid(w, rstEscapeRows([][]string{r.Columns})[0], rstEscapeRows(r.Texts()), r.Options.AlignRight)
+		})},
+		{Name: "rst-list", Extension: "rst", Renderer: RendererFunc(func(w io.Writer, r *Report) error {
+			return writeRSTList(w, rstEscapeRows([][]string{r.Columns})[0], rstEscapeRows(r.Texts()))
+		})},
+		{Name: "trend", Extension: "md", Renderer: RendererFunc(func(w io.Writer, r *Report) error {
+			return writeTrend(w, r.Trends(), newDurationFormat(r.Options), r.Options.AlignRight)
+		})},
+		{Name: "template", Extension: "txt", Renderer: RendererFunc(func(w io.Writer, r *Report) error {
+			if r.Options.Template == "" {
+				return fmt.Errorf("%w: template path is not set", ErrUnsupportedFormat)
+			}
+
+			return renderTemplate(w, r.Options.Template, buildTemplateData(r), newDurationFormat(r.Options))
+		})},
+	}
+
+	byName := make(map[string]Format, len(formats))
+	for _, format := range formats {
+		byName[format.Name] = format
+	}
+
+	return byName
+}
+
+// writeReportTable writes the table, followed by the number of units left out by filters
+// and by Options.Top.
+func writeReportTable(w io.Writer, r *Report) error {
+	err := renderAlignedTable(w, r.Columns, r.Texts(), r.Options.AlignRight)
+	if err != nil || r.Filtered == 0 && r.Omitted == 0 {
+		return err
+	}
+
+	shown := len(r.units)
+
+	if r.Filtered > 0 {
+		_, err = fmt.Fprintf(w, "\nFiltered out %d of %d units.\n", r.Filtered, shown+r.Omitted+r.Filtered)
+		if err != nil {
+			return fmt.Errorf("write filter summary: %w", err)
+		}
+	}
+
+	if r.Omitted > 0 {
+		_, err = fmt.Fprintf(w, "\nShowing the top %d of %d units.\n", shown, shown+r.Omitted)
+		if err != nil {
+			return fmt.Errorf("write top summary: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// FormatNames returns the names of the built-in formats in alphabetical order.
+func FormatNames() []string {
+	names := make([]string, 0)
+	for name := range Formats() {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Render writes the report to w in the named format, see Formats.
+func Render(w io.Writer, report *Report, format string) error {
+	return emit(w, report, []Output{{Format: format, Path: ""}})
+}
+
+// Emit writes the report to the outputs of its options like Run does: to files for outputs
+// with a path and to w for the others, the table by default.
+func Emit(w io.Writer, report *Report) error {
+	return emit(w, report, resolveOutputs(report.Options))
+}
+
+// resolveOutputs returns the outputs of a run. OutputFormat/OutputFile are kept as a shorthand
+// for a file output, and the table (or the template) is written to the writer unless another
+// output already targets it.
+func resolveOutputs(opts Options) []Output {
+	outputs := slices.Clone(opts.Outputs)
+
+	if opts.OutputFormat != "" {
+		outputs = append(outputs, Output{Format: opts.OutputFormat, Path: opts.OutputFile})
+
+		if opts.OutputFile == "" {
+			ext := opts.OutputFormat
+			if format, ok := Formats()[opts.OutputFormat]; ok {
+				ext = format.Extension
+			}
+
+			outputs[len(outputs)-1].Path = path.Join(opts.Directory, "report."+ext)
+		}
+	}
+
+	if slices.ContainsFunc(outputs, func(out Output) bool { return out.Path == "" }) {
+		return outputs
+	}
+
+	main := Output{Format: "table", Path: ""}
+	if opts.Template != "" {
+		main.Format = "template"
+	}
+
+	return append([]Output{main}, outputs...)
+}
+
+// emit renders the report to every output in one pass over the built report.
+// Formats are checked upfront, so an unknown one does not leave partial outputs behind.
+func emit(writer io.Writer, report *Report, outputs []Output) error {
+	formats := Formats()
+	renderers := make([]Renderer, 0, len(outputs))
+
+	for _, out := range outputs {
+		format, ok := formats[out.Format]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnsupportedFormat, out.Format)
+		}
+
+		renderers = append(renderers, format.Renderer)
+	}
+
+	for i, out := range outputs {
+		err := renderTo(writer, out.Path, renderers[i], report)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func renderTo(writer io.Writer, outPath string, renderer Renderer, report *Report) error {
+	if outPath == "" {
+		return renderer.Render(writer, report)
+	}
+
+	err := os.MkdirAll(filepath.Dir(outPath), outputDirPerm)
+	if err != nil {
+		return fmt.Errorf("create export directory: %w", err)
+	}
+
+	outFile, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("create export file: %w", err)
+	}
+	defer outFile.Close()
+
+	return renderer.Render(outFile, report)
+}
@@ -0,0 +1,158 @@
+package reporter
+
+import (
+	"time"
+)
+
+// CellStatus tells where the value of a report cell comes from.
+type CellStatus string
+
+const (
+	// CellLabel is the first cell of a row: a unit name, or a version when rotated.
+	CellLabel CellStatus = "label"
+	// CellPassed holds a duration of samples that all passed.
+	CellPassed CellStatus = "passed"
+	// CellFailed marks a unit with non-passed samples in the version.
+	CellFailed CellStatus = "failed"
+	// CellMissing marks a unit without samples in the version.
+	CellMissing CellStatus = "missing"
+)
+
+// Cell is a report value together with the duration it was formatted from.
+type Cell struct {
+	Text   string
+	Value  time.Duration
+	Status CellStatus
+}
+
+// OK reports whether the cell holds a duration.
+func (c Cell) OK() bool {
+	return c.Status == CellPassed
+}
+
+// Report is the typed model passed to renderers: the table as columns and rows of cells,
+// plus the data it was built from for renderers that need raw statistics.
+type Report struct {
+	Columns  []string
+	Rows     [][]Cell
+	Versions []string
+	Files    []string
+	Options  Options
+	// Filtered is the number of units left out by Options.Filters.
+	Filtered int
+	// Omitted is the number of units left out by Options.Top.
+	Omitted int
+	units   map[string]*unit
+	// order lists the units in the order of the table, see Options.SortBy.
+	order []string
+}
+
+// Texts returns the formatted rows as shown in the table.
+func (r *Report) Texts() [][]string {
+	rows := make([][]string, 0, len(r.Rows))
+
+	for _, cells := range r.Rows {
+		values := make([]string, 0, len(cells))
+		for _, cell := range cells {
+			values = append(values, cell.Text)
+		}
+
+		rows = append(rows, values)
+	}
+
+	return rows
+}
+
+// Stats returns raw statistics per unit and version, ordered by unit name and version.
+func (r *Report) Stats() []UnitStats {
+	return buildAggregates(r.units, r.Versions)
+}
+
+// Series returns raw statistics per unit, version and source report, the granularity
+// of time-series stores.
+func (r *Report) Series() []UnitStats {
+	return buildSeries(r.units, r.Versions)
+}
+
+func newCell(unitVal *unit, ver string, opts Options, format DurationFormat) Cell {
+	dur, err := unitVal.GetDuration(ver, opts.Ticks, opts.Median)
+	if err == nil {
+		return Cell{Text: format.Format(dur), Value: dur, Status: CellPassed}
+	}
+
+	if unitVal.Aggregate(ver).Total() == 0 {
+		return Cell{Text: err.Error(), Value: 0, Status: CellMissing}
+	}
+
+	return Cell{Text: err.Error(), Value: 0, Status: CellFailed}
+}
+
+func labelCell(text string) Cell {
+	return Cell{Text: text, Value: 0, Status: CellLabel}
+}
+
+// buildReport lays out units and versions as table rows; the first cell of a row is its label.
+// Units are ordered by Options.SortBy and those beyond Options.Top are dropped, and durations
+// are formatted as set by Options.DurationUnit and Options.DurationPrecision.
+func buildReport(units map[string]*unit, versions, files []string, opts Options) *Report {
+	unitList := orderUnits(units, versions, opts)
+	format := newDurationFormat(opts)
+	omitted := len(units) - len(unitList)
+
+	if omitted > 0 {
+		shown := make(map[string]*unit, len(unitList))
+		for _, unitKey := range unitList {
+			shown[unitKey] = units[unitKey]
+		}
+
+		units = shown
+	}
+
+	report := &Report{
+		Columns:  []string{},
+		Rows:     [][]Cell{},
+		Versions: versions,
+		Files:    files,
+		Options:  opts,
+		Filtered: 0,
+		Omitted:  omitted,
+		units:    units,
+		order:    unitList,
+	}
+
+	if opts.Rotate {
+		report.Columns = append(report.Columns, "Ver")
+		report.Columns = append(report.Columns, unitList...)
+
+		for _, ver := range versions {
+			values := make([]Cell, 0, 1+len(unitList))
+			values = append(values, labelCell(ver))
+
+			for _, unitKey := range unitList {
+				values = append(values, newCell(units[unitKey], ver, opts, format))
+			}
+
+			report.Rows = append(report.Rows, values)
+		}
+
+		return report
+	}
+
+	report.Columns = append(report.Columns, "Name")
+	report.Columns = append(report.Columns, versions...)
+
+	for _, unitKey := range unitList {
+		unitVal := units[unitKey]
+
+		values := make([]Cell, 0, 1+len(versions))
+		values = append(values, labelCell(unitVal.FullName()))
+
+		for _, ver := range versions {
+			values = append(values, newCell(unitVal, ver, opts, format))
+		}
+
+		report.Rows = append(report.Rows, values)
+	}
+
+	return report
+}
@@ -0,0 +1,549 @@
+package reporter
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/joshdk/go-junit"
+	"github.com/montanaflynn/stats"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+// roundBase is the largest scale, in seconds, durations are rounded to, see roundDuration.
+const roundBase = 100
+
+// Precompiled regex for extracting versions from filenames.
+var (
+	versionRE = regexp.MustCompile(`\d+\.((\d+|x)(\.(\d+|x))?)`)
+	junitRE   = regexp.MustCompile(`junit-(.+).xml`)
+)
+
+type Options struct {
+	Directory    string
+	Ticks        bool
+	Group        bool
+	Major        bool
+	Median       bool
+	Rotate       bool
+	OutputFormat string
+	OutputFile   string
+	// Timestamp overrides suite timestamps in time-series exports when non-zero.
+	Timestamp time.Time
+	// Template is a path to a text/template (html/template for .html files)
+	// rendered instead of the table.
+	Template string
+	// Outputs lists additional renderer targets; see Output.
+	Outputs []Output
+	// Progress, when set, is called after every parsed report.
+	Progress ProgressFunc
+	// Jobs bounds the number of reports parsed concurrently; values below one mean GOMAXPROCS.
+	Jobs int
+	// Inputs lists the report sources read instead of Directory, see Loader.Load.
+	Inputs []string
+	// KeepGoing skips unreadable reports instead of failing, see RunContext.
+	KeepGoing bool
+	// Filters select the tests of the report before they are grouped into units.
+	Filters []Filter
+	// VersionConstraint selects versions, e.g. ">= 7.0, < 10", see Aggregate.
+	VersionConstraint string
+	// ExcludePrerelease leaves out pre-release versions such as 7.3.0-beta1.
+	ExcludePrerelease bool
+	// LastVersions keeps only the last N of the selected versions when positive.
+	LastVersions int
+	// VersionOrder lists versions, e.g. branch names, in the order they are shown; the
+	// versions not listed follow in semantic order.
+	VersionOrder []string
+	// SortBy orders the units, e.g. "duration:7.1.0" or "delta", see SortKey; empty sorts by name.
+	SortBy string
+	// SortOrder is "asc" or "desc"; empty sorts names ascending and other keys descending.
+	SortOrder string
+	// Top keeps only the first N units in sort order when positive.
+	Top int
+	// DurationUnit prints every duration as a number of ns, us (µs), ms or s; empty scales
+	// each duration on its own.
+	DurationUnit string
+	// DurationPrecision is "N" significant digits or ".N" decimals; empty means three digits.
+	DurationPrecision string
+	// AlignRight aligns durations to the right in the table, trend and rst formats.
+	AlignRight bool
+}
+
+// defaultOptions returns options with every field unset, for callers that set only the
+// fields they need.
+func defaultOptions() Options {
+	return Options{
+		Directory:         "",
+		Ticks:             false,
+		Group:             false,
+		Major:             false,
+		Median:            false,
+		Rotate:            false,
+		OutputFormat:      "",
+		OutputFile:        "",
+		Timestamp:         time.Time{},
+		Template:          "",
+		Outputs:           nil,
+		Progress:          nil,
+		Jobs:              0,
+		Inputs:            nil,
+		KeepGoing:         false,
+		Filters:           nil,
+		VersionConstraint: "",
+		ExcludePrerelease: false,
+		LastVersions:      0,
+		VersionOrder:      nil,
+		SortBy:            "",
+		SortOrder:         "",
+		Top:               0,
+		DurationUnit:      "",
+		DurationPrecision: "",
+		AlignRight:        false,
+	}
+}
+
+type unit struct {
+	Class  string
+	Method string
+	t      []uTest
+}
+
+// uTest is a single sample of a unit. Label is the raw name taken from the report
+// filename and Time is the suite timestamp, when the report provides one.
+type uTest struct {
+	Ver   string
+	Label string
+	Time  time.Time
+	JUnit junit.Test
+}
+
+var (
+	ErrDash              = errors.New("-")
+	ErrFilesNotFound     = errors.New("files not found")
+	ErrUnsupportedFormat = errors.New("unsupported output format")
+	ErrInvalidTimestamp  = errors.New("invalid timestamp")
+	ErrMissingLabel      = errors.New("reading a report from stdin requires a version label")
+	ErrUnknownProfile    = errors.New("unknown profile")
+	ErrMissingTestName   = errors.New("testcase without a name")
+)
+
+func (u *unit) FullName() string {
+	return strings.TrimSuffix(u.Class, "Test") + ":" + strings.TrimPrefix(u.Method, "test")
+}
+
+func (u *unit) Push(ver string, t junit.Test) {
+	u.pushSample(uTest{Ver: ver, Label: "", Time: time.Time{}, JUnit: t})
+}
+
+func (u *unit) pushSample(sample uTest) {
+	u.t = append(u.t, sample)
+}
+
+func formatDuration(dur time.Duration) string {
+	return roundDuration(dur, defaultDigits).String()
+}
+
+func (u *unit) GetDuration(ver string, ticks bool, median bool) (time.Duration, error) {
+	var results []time.Duration
+
+	for _, testCase := range u.t {
+		if testCase.Ver == ver {
+			if testCase.JUnit.Status != "passed" {
+				return 0, ErrDash
+			}
+
+			results = append(results, testCase.JUnit.Duration)
+		}
+	}
+
+	if len(results) == 0 {
+		return 0, ErrDash
+	}
+
+	if ticks {
+		if median {
+			return u.getDurationMedian(results), nil
+		}
+
+		return u.getDurationAverage(results), nil
+	}
+
+	return u.getDurationSum(results), nil
+}
+
+func (u *unit) getDurationSum(input []time.Duration) time.Duration {
+	durTotal := time.Duration(0)
+	for _, i := range input {
+		durTotal += i
+	}
+
+	return durTotal
+}
+
+func (u *unit) getDurationAverage(input []time.Duration) time.Duration {
+	return time.Duration(int64(u.getDurationSum(input)) / int64(len(input)))
+}
+
+func (u *unit) getDurationMedian(input []time.Duration) time.Duration {
+	results := make([]float64, 0, len(input))
+	for _, i := range input {
+		results = append(results, float64(i))
+	}
+
+	median, err := stats.Median(results)
+	if err != nil {
+		return 0
+	}
+
+	return time.Duration(int64(median))
+}
+
+func newUnit(ver string, t junit.Test) unit {
+	return newSampleUnit(uTest{Ver: ver, Label: "", Time: time.Time{}, JUnit: t})
+}
+
+func newSampleUnit(sample uTest) unit {
+	namespaces := strings.Split(sample.JUnit.Classname, ".")
+	className := namespaces[len(namespaces)-1]
+	method := strings.Fields(sample.JUnit.Name)[0]
+
+	return unit{Class: className, Method: method, t: []uTest{sample}}
+}
+
+func depthSuite(suite junit.Suite) []junit.Test {
+	tests := suite.Tests
+	for _, one := range suite.Suites {
+		tests = append(tests, depthSuite(one)...)
+	}
+
+	return tests
+}
+
+// suitesTimestamp returns the first parsable "timestamp" attribute found in the suites
+// or the zero time when the report does not carry one.
+func suitesTimestamp(suites []junit.Suite) time.Time {
+	for _, suite := range suites {
+		if raw, ok := suite.Properties["timestamp"]; ok {
+			stamp, ok := parseSuiteTimestamp(raw)
+			if ok {
+				return stamp
+			}
+		}
+
+		stamp := suitesTimestamp(suite.Suites)
+		if !stamp.IsZero() {
+			return stamp
+		}
+	}
+
+	return time.Time{}
+}
+
+// parseSuiteTimestamp parses a testsuite "timestamp" attribute.
+func parseSuiteTimestamp(raw string) (time.Time, bool) {
+	layouts := []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05"}
+
+	for _, layout := range layouts {
+		stamp, err := time.Parse(layout, raw)
+		if err == nil {
+			return stamp, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// ParseVersionFromPath extracts the version string from a filename path using the same
+// rules as Run: when group==true it extracts numeric version-like pattern, optionally
+// collapsing to major.x when major==true. When group==false it extracts the substring
+// matched by `junit-(.+).xml`.
+func ParseVersionFromPath(_path string, group bool, major bool) string {
+	if group {
+		ver := string(versionRE.Find([]byte(_path)))
+		if major {
+			parts := strings.Split(ver, ".")
+			if len(parts) > 0 {
+				return parts[0] + ".x"
+			}
+		}
+
+		return ver
+	}
+
+	m := junitRE.FindStringSubmatch(_path)
+	if len(m) > 1 {
+		return m[1]
+	}
+
+	return ""
+}
+
+func discoverJUnitFiles(dir string) ([]string, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read directory: %w", err)
+	}
+
+	var filenames []string
+
+	for _, info := range files {
+		if info.Type().IsRegular() && (isReportName(info.Name()) || isArchiveName(info.Name())) {
+			filenames = append(filenames, path.Join(dir, info.Name()))
+		}
+	}
+
+	if len(filenames) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrFilesNotFound, dir)
+	}
+
+	return filenames, nil
+}
+
+// groupUnits collects the tests of the loaded reports that pass the filters into units and
+// returns versions in the order of the first report that introduced them, and the number of
+// units filtered out. A unit counts as filtered out only when none of its tests is kept, e.g.
+// when a namespace filter keeps the unit of one package and drops the same name in another.
+func groupUnits(files []loadedFile, opts Options) (map[string]*unit, []string, int) {
+	units := map[string]*unit{}
+	verKeys := map[string]bool{}
+	filtered := map[string]bool{}
+
+	var versions []string
+
+	for _, file := range files {
+		ver := file.version(opts.Group, opts.Major)
+
+		if _, ok := verKeys[ver]; !ok {
+			versions = append(versions, ver)
+			verKeys[ver] = true
+		}
+
+		for _, test := range file.Tests {
+			sample := uTest{Ver: ver, Label: file.Label, Time: file.Time, JUnit: test}
+			unitVal := newSampleUnit(sample)
+
+			if !keepTest(opts.Filters, test) {
+				filtered[unitVal.FullName()] = true
+
+				continue
+			}
+
+			if elem, ok := units[unitVal.FullName()]; ok {
+				elem.pushSample(sample)
+
+				continue
+			}
+
+			units[unitVal.FullName()] = &unitVal
+		}
+	}
+
+	omitted := 0
+
+	for name := range filtered {
+		if _, kept := units[name]; !kept {
+			omitted++
+		}
+	}
+
+	return units, versions, omitted
+}
+
+func writeCSV(w io.Writer, columns []string, rows [][]string) error {
+	csvWriter := csv.NewWriter(w)
+
+	err := csvWriter.Write(columns)
+	if err != nil {
+		return fmt.Errorf("write csv header: %w", err)
+	}
+
+	for _, row := range rows {
+		err = csvWriter.Write(row)
+		if err != nil {
+			return fmt.Errorf("write csv row: %w", err)
+		}
+	}
+
+	csvWriter.Flush()
+
+	err = csvWriter.Error()
+	if err != nil {
+		return fmt.Errorf("flush csv: %w", err)
+	}
+
+	return nil
+}
+
+func writeJSON(w io.Writer, columns []string, rows [][]string) error {
+	objs := make([]map[string]string, 0, len(rows))
+
+	for _, row := range rows {
+		obj := map[string]string{}
+
+		for i, header := range columns {
+			val := ""
+			if i < len(row) {
+				val = row[i]
+			}
+
+			obj[header] = val
+		}
+
+		objs = append(objs, obj)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(objs)
+	if err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+
+	return nil
+}
+
+// renderTable configures the table writer, writes header and rows, and renders output.
+func renderTable(w io.Writer, columns []string, rows [][]string) error {
+	return renderAlignedTable(w, columns, rows, false)
+}
+
+// renderAlignedTable is renderTable that, with alignRight, aligns all but the first column
+// of the rows to the right.
+func renderAlignedTable(w io.Writer, columns []string, rows [][]string, alignRight bool) error {
+	tbl := tablewriter.NewWriter(w)
+	tbl.Options(
+		tablewriter.WithHeaderAutoFormat(tw.Off),
+		tablewriter.WithRowAutoFormat(tw.Off),
+		tablewriter.WithRowAutoWrap(tw.WrapNone),
+		tablewriter.WithRendition(tw.Rendition{
+			Borders: tw.Border{Left: tw.On, Top: tw.Off, Right: tw.On, Bottom: tw.Off, Overwrite: false},
+			Symbols: tw.NewSymbols(tw.StyleMarkdown),
+			Settings: tw.Settings{
+				Separators: tw.Separators{
+					ShowHeader:     tw.Off,
+					ShowFooter:     tw.Off,
+					BetweenRows:    tw.Off,
+					BetweenColumns: tw.On,
+				},
+				Lines: tw.Lines{
+					ShowTop:        tw.Off,
+					ShowBottom:     tw.Off,
+					ShowHeaderLine: tw.On,
+					ShowFooterLine: tw.Off,
+				},
+				CompactMode: tw.Off,
+			},
+			Streaming: false,
+		}),
+	)
+
+	if alignRight {
+		tbl.Options(tablewriter.WithRowAlignmentConfig(tw.CellAlignment{
+			Global:    tw.AlignRight,
+			PerColumn: []tw.Align{tw.AlignLeft},
+		}))
+	}
+
+	hdr := make([]any, len(columns))
+	for i, c := range columns {
+		hdr[i] = c
+	}
+
+	tbl.Header(hdr...)
+
+	for _, values := range rows {
+		err := tbl.Append(values)
+		if err != nil {
+			return fmt.Errorf("append table row: %w", err)
+		}
+	}
+
+	err := tbl.Render()
+	if err != nil {
+		return fmt.Errorf("render table: %w", err)
+	}
+
+	return nil
+}
+
+// CompareVersions implements the same comparison used in Run to sort version strings.
+// Returns true if a < b.
+func CompareVersions(a, b string) bool {
+	normalizedA := strings.ReplaceAll(a, "x", "0")
+	normalizedB := strings.ReplaceAll(b, "x", "0")
+	ver1, err1 := version.NewVersion(normalizedA)
+
+	ver2, err2 := version.NewVersion(normalizedB)
+	if err1 != nil || err2 != nil {
+		return normalizedA < normalizedB
+	}
+
+	return ver1.LessThan(ver2)
+}
+
+// Run parses junit xml files from the provided directory according to options
+// and renders a table to the provided writer.
+func Run(writer io.Writer, opts Options) error {
+	return RunContext(context.Background(), writer, opts)
+}
+
+// RunContext is Run that stops loading once ctx is done. With Options.KeepGoing the
+// report is rendered without unreadable reports and a *SkippedReportsError lists them.
+func RunContext(ctx context.Context, writer io.Writer, opts Options) error {
+	err := opts.Validate()
+	if err != nil {
+		return err
+	}
+
+	data, err := LoadOptions(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	report := Aggregate(data, opts)
+
+	err = opts.ValidateSort(report.Versions)
+	if err != nil {
+		return err
+	}
+
+	err = Emit(writer, report)
+	if err != nil {
+		return err
+	}
+
+	if skipped := data.Errors(); len(skipped) > 0 {
+		return &SkippedReportsError{Errors: skipped}
+	}
+
+	return nil
+}
+
+// SkippedReportsError reports the files left out of a report in keep-going mode.
+type SkippedReportsError struct {
+	Errors []*ReportError
+}
+
+func (e *SkippedReportsError) Error() string {
+	return fmt.Sprintf("skipped %d unreadable report(s)", len(e.Errors))
+}
+
+func (e *SkippedReportsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
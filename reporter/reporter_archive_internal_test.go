@@ -0,0 +1,193 @@
+package reporter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeGzip(t *testing.T, path string, data []byte) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create %s: %v", path, err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+
+	_, err = gz.Write(data)
+	if err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+
+	err = gz.Close()
+	if err != nil {
+		t.Fatalf("close %s: %v", path, err)
+	}
+}
+
+// archiveEntry is a file written to a test archive.
+type archiveEntry struct {
+	name string
+	data []byte
+}
+
+func writeZip(t *testing.T, path string, entries ...archiveEntry) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create %s: %v", path, err)
+	}
+	defer f.Close()
+
+	archive := zip.NewWriter(f)
+
+	for _, file := range entries {
+		entry, err := archive.Create(file.name)
+		if err != nil {
+			t.Fatalf("create entry %s: %v", file.name, err)
+		}
+
+		_, err = entry.Write(file.data)
+		if err != nil {
+			t.Fatalf("write entry %s: %v", file.name, err)
+		}
+	}
+
+	_, err = archive.Create("README.txt")
+	if err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+
+	err = archive.Close()
+	if err != nil {
+		t.Fatalf("close %s: %v", path, err)
+	}
+}
+
+func writeTarGz(t *testing.T, path string, entries ...archiveEntry) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create %s: %v", path, err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	archive := tar.NewWriter(gz)
+
+	for _, file := range entries {
+		err = archive.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: file.name, Size: int64(len(file.data)), Mode: 0o600})
+		if err != nil {
+			t.Fatalf("write header %s: %v", file.name, err)
+		}
+
+		_, err = archive.Write(file.data)
+		if err != nil {
+			t.Fatalf("write entry %s: %v", file.name, err)
+		}
+	}
+
+	err = archive.Close()
+	if err != nil {
+		t.Fatalf("close tar %s: %v", path, err)
+	}
+
+	err = gz.Close()
+	if err != nil {
+		t.Fatalf("close gzip %s: %v", path, err)
+	}
+}
+
+func TestLoad_CompressedAndArchived(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("..", "build", "junit-7.0.0.xml"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	dir := t.TempDir()
+	writeGzip(t, filepath.Join(dir, "junit-1.0.0.xml.gz"), data)
+	writeZip(t, filepath.Join(dir, "artifacts-9.9.9.zip"), archiveEntry{name: "ci/junit-2.0.0.xml", data: data})
+	writeTarGz(t, filepath.Join(dir, "bundle-8.8.8.tar.gz"), archiveEntry{name: "nested/junit-3.0.0.xml", data: data})
+
+	loaded, err := Load(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	want := []string{
+		filepath.Join(dir, "artifacts-9.9.9.zip") + "!/ci/junit-2.0.0.xml",
+		filepath.Join(dir, "bundle-8.8.8.tar.gz") + "!/nested/junit-3.0.0.xml",
+		filepath.Join(dir, "junit-1.0.0.xml.gz"),
+	}
+
+	files := loaded.Files()
+	if len(files) != len(want) {
+		t.Fatalf("expected files %v, got %v", want, files)
+	}
+
+	for i := range want {
+		if files[i] != want[i] {
+			t.Fatalf("expected files %v, got %v", want, files)
+		}
+
+		if len(loaded.files[i].Tests) != 826 {
+			t.Fatalf("%s: expected 826 tests, got %d", files[i], len(loaded.files[i].Tests))
+		}
+	}
+
+	opts := defaultOptions()
+	opts.Directory = dir
+	opts.Group = true
+
+	report := Aggregate(loaded, opts)
+	if got := report.Versions; len(got) != 3 || got[0] != "1.0.0" || got[1] != "2.0.0" || got[2] != "3.0.0" {
+		t.Fatalf("expected versions from entry names, got %v", got)
+	}
+}
+
+func TestLoad_ArchiveKeepGoing(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	valid := []byte(`<testsuite><testcase classname="a.CartTest" name="testPay" time="1"/></testsuite>`)
+	broken := []byte("<testsuite><testcase>")
+	entries := []archiveEntry{
+		{name: "junit-1.0.0.xml", data: valid},
+		{name: "junit-2.0.0.xml", data: broken},
+		{name: "junit-3.0.0.xml", data: valid},
+	}
+
+	writeZip(t, filepath.Join(dir, "a.zip"), entries...)
+	writeTarGz(t, filepath.Join(dir, "b.tar.gz"), entries...)
+
+	loaded, err := Loader{Jobs: 0, Progress: nil, Stdin: nil, KeepGoing: true, Cache: nil}.Load(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got := loaded.Files(); len(got) != 4 {
+		t.Fatalf("expected the valid entries of both archives, got %v", got)
+	}
+
+	skipped := loaded.Errors()
+	if len(skipped) != 2 || skipped[0].Path != filepath.Join(dir, "a.zip")+"!/junit-2.0.0.xml" ||
+		skipped[1].Path != filepath.Join(dir, "b.tar.gz")+"!/junit-2.0.0.xml" {
+		t.Fatalf("expected the broken entries to be skipped, got %v", skipped)
+	}
+
+	_, err = Load(context.Background(), filepath.Join(dir, "a.zip"))
+	if err == nil {
+		t.Fatal("expected a broken entry to fail the archive without KeepGoing")
+	}
+}
@@ -0,0 +1,126 @@
+package reporter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+func historySeries(name string, means ...int) []HistoryRecord {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := make([]HistoryRecord, 0, len(means))
+
+	for i, ms := range means {
+		value := time.Duration(ms) * time.Millisecond
+		records = append(records, HistoryRecord{
+			Time: start.Add(time.Duration(i) * time.Hour), Commit: string(rune('a' + i)), Version: "7.x", Name: name,
+			Sum: value, Mean: value, Median: value, Min: value, Max: value, Passed: 1, Failed: 0, Skipped: 0, Errors: 0,
+		})
+	}
+
+	return records
+}
+
+func TestDetectChangePoints(t *testing.T) {
+	t.Parallel()
+
+	records := historySeries("Cart:Pay", 100, 102, 99, 101, 100, 98, 131, 129, 130, 132, 128, 130)
+	records = append(records, historySeries("Cart:Flat", 100, 104, 97, 101, 103, 99, 100, 102, 98, 101, 99, 103)...)
+	records = append(records, historySeries("Gift:Steps", 50, 50, 51, 50, 40, 41, 40, 40, 60, 61, 60, 60)...)
+
+	points := DetectChangePoints(records, ChangePointOptions{Median: false, MinSegment: 0, Threshold: 0, MinChange: 0})
+	if len(points) != 3 {
+		t.Fatalf("expected 3 change points, got %+v", points)
+	}
+
+	pay := points[0]
+	if pay.Name != "Cart:Pay" || pay.Commit != "g" || !pay.Regression() || !pay.Latest ||
+		pay.Before != 100*time.Millisecond || pay.After != 130*time.Millisecond {
+		t.Fatalf("unexpected Cart:Pay change: %+v", pay)
+	}
+
+	// the segments of a change end at the neighbouring changes
+	speedup := points[1]
+	if speedup.Name != "Gift:Steps" || speedup.Commit != "e" || speedup.Regression() || speedup.Latest ||
+		speedup.Before != 50250*time.Microsecond || speedup.After != 40250*time.Microsecond {
+		t.Fatalf("expected the Gift:Steps speed-up first, got %+v", speedup)
+	}
+
+	slowdown := points[2]
+	if slowdown.Name != "Gift:Steps" || slowdown.Commit != "i" || !slowdown.Regression() || !slowdown.Latest ||
+		slowdown.Before != 40250*time.Microsecond || slowdown.After != 60250*time.Microsecond {
+		t.Fatalf("expected the Gift:Steps slow-down second, got %+v", slowdown)
+	}
+
+	strict := DetectChangePoints(records, ChangePointOptions{Median: true, MinSegment: 0, Threshold: 0, MinChange: 31})
+	if len(strict) != 1 || strict[0].Commit != "i" {
+		t.Fatalf("expected only the larger step with MinChange 31, got %+v", strict)
+	}
+
+	var (
+		out       bytes.Buffer
+		durations DurationFormat
+	)
+
+	err := RenderChangePoints(&out, points, "table", durations)
+	if err != nil {
+		t.Fatalf("RenderChangePoints failed: %v", err)
+	}
+
+	if !strings.HasPrefix(out.String(), "### Change points") || !strings.Contains(out.String(), "+30.0% slower") {
+		t.Fatalf("unexpected section:\n%s", out.String())
+	}
+
+	out.Reset()
+
+	err = RenderChangePoints(&out, points[:1], "json", durations)
+	if err != nil || !strings.Contains(out.String(), `"latest": true`) {
+		t.Fatalf("unexpected json (%v):\n%s", err, out.String())
+	}
+
+	err = RenderChangePoints(&out, points, "yaml", durations)
+	if !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestLatestRegression(t *testing.T) {
+	t.Parallel()
+
+	opts := ChangePointOptions{Median: false, MinSegment: 0, Threshold: 0, MinChange: 0}
+
+	// slower for a while, then fixed and faster than before
+	fixed := DetectChangePoints(historySeries("Cart:Pay", 100, 101, 99, 100, 130, 131, 129, 130, 60, 59, 61, 60), opts)
+	if len(fixed) != 2 || !fixed[0].Regression() || LatestRegression(fixed) {
+		t.Fatalf("expected a fixed slow-down not to gate, got %+v", fixed)
+	}
+
+	slower := DetectChangePoints(historySeries("Cart:Pay", 100, 101, 99, 100, 80, 81, 79, 80, 120, 119, 121, 120), opts)
+	if !LatestRegression(slower) {
+		t.Fatalf("expected the latest slow-down to gate, got %+v", slower)
+	}
+}
+
+func TestReportChangePoints(t *testing.T) {
+	t.Parallel()
+
+	pay := newUnit("7.x", makeTest("testPay", "a.CartTest", junit.StatusPassed, time.Second))
+	report := buildReport(map[string]*unit{pay.FullName(): &pay}, []string{"7.x"}, nil, defaultOptions())
+
+	records := historySeries("Cart:Pay", 100, 102, 99, 101, 100, 98, 131, 129, 130, 132, 128, 130)
+	records = append(records, historySeries("Gift:Steps", 50, 50, 51, 50, 40, 41, 40, 40, 60, 61, 60, 60)...)
+
+	for _, record := range historySeries("Cart:Pay", 100, 102, 99, 101, 100, 98, 131, 129, 130, 132, 128, 130) {
+		record.Version = "6.x"
+		records = append(records, record)
+	}
+
+	points := report.ChangePoints(records, ChangePointOptions{Median: false, MinSegment: 0, Threshold: 0, MinChange: 0})
+	if len(points) != 1 || points[0].Name != "Cart:Pay" || points[0].Version != "7.x" {
+		t.Fatalf("expected only the change of the unit and version in the report, got %+v", points)
+	}
+}
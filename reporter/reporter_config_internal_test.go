@@ -0,0 +1,169 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "junit-reporter.yaml")
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	return path
+}
+
+func TestLoadConfig_Options(t *testing.T) {
+	t.Parallel()
+
+	path := writeConfig(t, `
+profiles:
+  weekly:
+    inputs: [reports, "7.x=/abs/junit.xml", "nightly=-"]
+    group: true
+    major: true
+    median: true
+    outputs: [csv=out/report.csv, json]
+    timestamp: "1700000000"
+    jobs: 2
+    keep_going: true
+    constraint: ">= 7, < 10"
+    exclude_prerelease: true
+    last_versions: 3
+    version_order: [main, release]
+    sort: "delta:7.0..7.1"
+    sort_order: asc
+    top: 5
+    unit: ms
+    precision: ".1"
+    align_right: true
+`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	opts, err := cfg.Options("weekly")
+	if err != nil {
+		t.Fatalf("Options failed: %v", err)
+	}
+
+	dir := filepath.Dir(path)
+	wantInputs := []string{filepath.Join(dir, "reports"), "7.x=/abs/junit.xml", "nightly=-"}
+
+	if strings.Join(opts.Inputs, ",") != strings.Join(wantInputs, ",") {
+		t.Fatalf("expected inputs %v, got %v", wantInputs, opts.Inputs)
+	}
+
+	if !opts.Group || !opts.Major || !opts.Median || opts.Ticks || opts.Rotate || opts.Jobs != 2 || !opts.KeepGoing {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+
+	if opts.VersionConstraint != ">= 7, < 10" || !opts.ExcludePrerelease || opts.LastVersions != 3 ||
+		strings.Join(opts.VersionOrder, ",") != "main,release" {
+		t.Fatalf("unexpected version selection: %+v", opts)
+	}
+
+	if opts.SortBy != "delta:7.0..7.1" || opts.SortOrder != "asc" || opts.Top != 5 {
+		t.Fatalf("unexpected sorting: %+v", opts)
+	}
+
+	if opts.DurationUnit != "ms" || opts.DurationPrecision != ".1" || !opts.AlignRight {
+		t.Fatalf("unexpected duration format: %+v", opts)
+	}
+
+	if len(opts.Outputs) != 2 || opts.Outputs[0].Path != filepath.Join(dir, "out", "report.csv") || opts.Outputs[1].Path != "" {
+		t.Fatalf("unexpected outputs: %+v", opts.Outputs)
+	}
+
+	if opts.Timestamp.Unix() != 1700000000 {
+		t.Fatalf("unexpected timestamp: %v", opts.Timestamp)
+	}
+
+	_, err = cfg.Options("missing")
+	if !errors.Is(err, ErrUnknownProfile) {
+		t.Fatalf("expected ErrUnknownProfile, got %v", err)
+	}
+}
+
+func TestLoadConfig_UnknownField(t *testing.T) {
+	t.Parallel()
+
+	path := writeConfig(t, "profiles:\n  default:\n    tick: true\n")
+
+	_, err := LoadConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "tick") {
+		t.Fatalf("expected an error for the unknown field, got %v", err)
+	}
+}
+
+func TestConfig_RunProfilesToFiles(t *testing.T) {
+	t.Parallel()
+
+	build, err := filepath.Abs(filepath.Join("..", "build"))
+	if err != nil {
+		t.Fatalf("abs: %v", err)
+	}
+
+	path := writeConfig(t, `
+profiles:
+  ticks:
+    inputs: [`+build+`]
+    ticks: true
+    outputs: [table=ticks.txt]
+  printed:
+    inputs: [`+build+`]
+    rotate: true
+`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if names := cfg.Names(); len(names) != 2 || names[0] != "printed" || names[1] != "ticks" {
+		t.Fatalf("expected sorted profile names, got %v", names)
+	}
+
+	var stdout strings.Builder
+
+	for _, name := range cfg.Names() {
+		err = cfg.Run(context.Background(), &stdout, name, nil)
+		if err != nil {
+			t.Fatalf("Run %s failed: %v", name, err)
+		}
+	}
+
+	want, err := os.ReadFile(filepath.Join(build, "runs", "run-rotate.txt"))
+	if err != nil {
+		t.Fatalf("read baseline: %v", err)
+	}
+
+	if stdout.String() != string(want) {
+		t.Fatalf("expected only the printed profile on stdout, got:\n%s", stdout.String())
+	}
+
+	got, err := os.ReadFile(filepath.Join(filepath.Dir(path), "ticks.txt"))
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	want, err = os.ReadFile(filepath.Join(build, "runs", "run-ticks.txt"))
+	if err != nil {
+		t.Fatalf("read baseline: %v", err)
+	}
+
+	if string(got) != string(want) {
+		t.Fatalf("ticks output differs from baseline:\n%s", got)
+	}
+}
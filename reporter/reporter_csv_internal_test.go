@@ -11,16 +11,10 @@ func TestRun_ExportCSV(t *testing.T) {
 	t.Parallel()
 	td := t.TempDir()
 	out := filepath.Join(td, "out.csv")
-	opts := Options{
-		Directory:    filepath.Join("..", "..", "build"),
-		Ticks:        false,
-		Group:        false,
-		Major:        false,
-		Median:       false,
-		Rotate:       false,
-		OutputFormat: "csv",
-		OutputFile:   out,
-	}
+	opts := defaultOptions()
+	opts.Directory = filepath.Join("..", "build")
+	opts.OutputFormat = "csv"
+	opts.OutputFile = out
 
 	var b strings.Builder
 
@@ -43,16 +37,10 @@ func TestRun_ExportJSON(t *testing.T) {
 	t.Parallel()
 	td := t.TempDir()
 	out := filepath.Join(td, "out.json")
-	opts := Options{
-		Directory:    filepath.Join("..", "..", "build"),
-		Ticks:        false,
-		Group:        false,
-		Major:        false,
-		Median:       false,
-		Rotate:       false,
-		OutputFormat: "json",
-		OutputFile:   out,
-	}
+	opts := defaultOptions()
+	opts.Directory = filepath.Join("..", "build")
+	opts.OutputFormat = "json"
+	opts.OutputFile = out
 
 	var b strings.Builder
 
@@ -0,0 +1,122 @@
+package reporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashboard(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeDiffReport(t, dir, "junit-1.0.0.xml", `<testcase classname="a.CartTest" name="testPay" time="1"/>`)
+
+	opts := defaultOptions()
+	opts.Directory = dir
+	opts.Ticks = true
+
+	handler, err := NewDashboard(opts)
+	if err != nil {
+		t.Fatalf("NewDashboard failed: %v", err)
+	}
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	get := func(path string, want int, into any) {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != want {
+			t.Fatalf("GET %s: expected status %d, got %d", path, want, resp.StatusCode)
+		}
+
+		if into != nil {
+			err = json.NewDecoder(resp.Body).Decode(into)
+			if err != nil {
+				t.Fatalf("GET %s: decode: %v", path, err)
+			}
+		}
+	}
+
+	get("/", http.StatusOK, nil)
+
+	var versions []string
+
+	get("/api/versions", http.StatusOK, &versions)
+
+	if strings.Join(versions, ",") != "1.0.0" {
+		t.Fatalf("unexpected versions: %v", versions)
+	}
+
+	// files are re-read on every request
+	writeDiffReport(t, dir, "junit-2.0.0.xml",
+		`<testcase classname="a.CartTest" name="testPay" time="2"/>`,
+		`<testcase classname="a.CartTest" name="testFresh" time="1"/>`)
+
+	var units []TemplateUnit
+
+	get("/api/units", http.StatusOK, &units)
+
+	if len(units) != 2 || units[1].Name != "Cart:Pay" || units[1].Cell("2.0.0").Value != 2*time.Second {
+		t.Fatalf("unexpected units: %+v", units)
+	}
+
+	var trends []map[string]any
+
+	get("/api/trend", http.StatusOK, &trends)
+
+	if len(trends) != 1 || trends[0]["name"] != "Cart:Pay" || trends[0]["slowest_version"] != "2.0.0" {
+		t.Fatalf("unexpected trends: %+v", trends)
+	}
+
+	var diff Diff
+
+	get("/api/compare?a=1.0.0&b=2.0.0", http.StatusOK, &diff)
+
+	if len(diff.Units) != 1 || diff.Units[0].Delta != time.Second || strings.Join(diff.Added, ",") != "Cart:Fresh" {
+		t.Fatalf("unexpected diff: %+v", diff)
+	}
+
+	var apiErr map[string]string
+
+	get("/api/compare?a=1.0.0&b=3.0.0", http.StatusBadRequest, &apiErr)
+
+	if !strings.Contains(apiErr["error"], "unknown version") {
+		t.Fatalf("unexpected error: %v", apiErr)
+	}
+
+	// a request that runs out of time while loading the reports
+	expired, cancel := context.WithTimeout(t.Context(), 0)
+	defer cancel()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequestWithContext(expired, http.MethodGet, "/api/units", nil))
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected status %d for an expired request, got %d: %s", http.StatusGatewayTimeout, rec.Code, rec.Body)
+	}
+
+	opts.Inputs = []string{"7.0=-"}
+
+	_, err = NewDashboard(opts)
+	if !errors.Is(err, ErrWatchStdin) {
+		t.Fatalf("expected ErrWatchStdin for stdin, got %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMannWhitneyU(t *testing.T) {
+	t.Parallel()
+
+	ms := func(values ...int) []time.Duration {
+		durations := make([]time.Duration, 0, len(values))
+		for _, value := range values {
+			durations = append(durations, time.Duration(value)*time.Millisecond)
+		}
+
+		return durations
+	}
+
+	separated := mannWhitneyU(ms(1, 2, 3, 4, 5), ms(6, 7, 8, 9, 10))
+	if math.Abs(separated-0.0122) > 0.0005 {
+		t.Fatalf("expected p ~ 0.0122 for separated samples, got %f", separated)
+	}
+
+	if same := mannWhitneyU(ms(5, 5, 5), ms(5, 5, 5)); same != 1 {
+		t.Fatalf("expected p = 1 for identical samples, got %f", same)
+	}
+
+	if overlapping := mannWhitneyU(ms(1, 3, 5, 7), ms(2, 4, 6, 8)); overlapping < 0.5 {
+		t.Fatalf("expected a large p for interleaved samples, got %f", overlapping)
+	}
+}
+
+func writeDiffReport(t *testing.T, dir, name string, cases ...string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	doc := "<testsuites><testsuite>" + strings.Join(cases, "") + "</testsuite></testsuites>"
+
+	err := os.WriteFile(path, []byte(doc), 0o600)
+	if err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+
+	return path
+}
+
+func TestDiffDatasets(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	passed := func(method, seconds string) string {
+		return `<testcase classname="a.CartTest" name="` + method + `" time="` + seconds + `"/>`
+	}
+	failed := func(method string) string {
+		return `<testcase classname="a.CartTest" name="` + method + `" time="1"><failure/></testcase>`
+	}
+
+	oldPath := writeDiffReport(t, dir, "old.xml",
+		passed("testPay", "1"), passed("testPay", "1.1"), passed("testPay", "0.9"),
+		passed("testBreaks", "1"), failed("testHeals"), passed("testGone", "1"))
+	newPath := writeDiffReport(t, dir, "new.xml",
+		passed("testPay", "2"), passed("testPay", "2.2"), passed("testPay", "1.8"),
+		failed("testBreaks"), passed("testHeals", "1"), passed("testFresh", "1"))
+
+	old, err := Load(context.Background(), oldPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	cur, err := Load(context.Background(), newPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	diff := DiffDatasets(old, cur, false)
+
+	check := func(what string, got []string, want ...string) {
+		t.Helper()
+
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("%s: expected %v, got %v", what, want, got)
+		}
+	}
+
+	check("added", diff.Added, "Cart:Fresh")
+	check("removed", diff.Removed, "Cart:Gone")
+	check("newly failing", diff.NewlyFailing, "Cart:Breaks")
+	check("newly passing", diff.NewlyPassing, "Cart:Heals")
+
+	if len(diff.Units) != 3 || diff.Units[2].Name != "Cart:Pay" {
+		t.Fatalf("unexpected units: %+v", diff.Units)
+	}
+
+	pay := diff.Units[2]
+	if !pay.OK || pay.Delta != time.Second || pay.Percent == nil || *pay.Percent != 100 || pay.PValue == nil {
+		t.Fatalf("unexpected Cart:Pay diff: %+v", pay)
+	}
+
+	if diff.Units[0].OK || diff.Units[0].PValue != nil {
+		t.Fatalf("expected Cart:Breaks without durations on the new side: %+v", diff.Units[0])
+	}
+
+	var (
+		table     bytes.Buffer
+		durations DurationFormat
+	)
+
+	err = RenderDiff(&table, diff, "table", durations)
+	if err != nil {
+		t.Fatalf("RenderDiff failed: %v", err)
+	}
+
+	for _, want := range []string{"| Cart:Pay", "+1s", "+100.0%", "Added (1):\n- Cart:Fresh", "Newly failing (1):\n- Cart:Breaks"} {
+		if !strings.Contains(table.String(), want) {
+			t.Fatalf("expected %q in:\n%s", want, table.String())
+		}
+	}
+
+	millis, err := ParseDurationFormat("ms", ".1")
+	if err != nil {
+		t.Fatalf("ParseDurationFormat failed: %v", err)
+	}
+
+	table.Reset()
+
+	err = RenderDiff(&table, diff, "table", millis)
+	if err != nil || !strings.Contains(table.String(), "+1000.0ms") {
+		t.Fatalf("expected the delta in fixed milliseconds (%v):\n%s", err, table.String())
+	}
+
+	var out bytes.Buffer
+
+	err = RenderDiff(&out, diff, "json", durations)
+	if err != nil {
+		t.Fatalf("RenderDiff json failed: %v", err)
+	}
+
+	var decoded Diff
+
+	err = json.Unmarshal(out.Bytes(), &decoded)
+	if err != nil || len(decoded.Units) != 3 || decoded.Stat != "mean" {
+		t.Fatalf("unexpected json diff (%v):\n%s", err, out.String())
+	}
+
+	if !strings.Contains(out.String(), `"median": `) || strings.Contains(out.String(), `"Version"`) {
+		t.Fatalf("expected snake_case statistics without the side version:\n%s", out.String())
+	}
+}
@@ -0,0 +1,105 @@
+package reporter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+func TestDurationFormat(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		unit, precision string
+		dur             time.Duration
+		want            string
+	}{
+		{unit: "", precision: "", dur: 77_912 * time.Millisecond, want: "1m17.9s"},
+		{unit: "", precision: "", dur: 489_312 * time.Microsecond, want: "489ms"},
+		{unit: "", precision: "2", dur: 77_912 * time.Millisecond, want: "1m18s"},
+		{unit: "", precision: "5", dur: 489_312 * time.Microsecond, want: "489.31ms"},
+		{unit: "", precision: ".1", dur: 77_912 * time.Millisecond, want: "77.9s"},
+		{unit: "", precision: ".2", dur: 489_312 * time.Microsecond, want: "489.31ms"},
+		{unit: "s", precision: "", dur: 77_912 * time.Millisecond, want: "77.9s"},
+		{unit: "s", precision: "", dur: 489_312 * time.Microsecond, want: "0.489s"},
+		{unit: "s", precision: ".2", dur: 489_312 * time.Microsecond, want: "0.49s"},
+		{unit: "ms", precision: "", dur: 77_912 * time.Millisecond, want: "77912ms"},
+		{unit: "us", precision: ".0", dur: 489_312 * time.Microsecond, want: "489312µs"},
+		{unit: "ns", precision: "", dur: 1500, want: "1500ns"},
+		{unit: "ms", precision: "", dur: 0, want: "0ms"},
+	}
+
+	for _, tc := range cases {
+		format, err := ParseDurationFormat(tc.unit, tc.precision)
+		if err != nil {
+			t.Fatalf("ParseDurationFormat(%q, %q) failed: %v", tc.unit, tc.precision, err)
+		}
+
+		if got := format.Format(tc.dur); got != tc.want {
+			t.Fatalf("unit %q precision %q: expected %s for %v, got %s", tc.unit, tc.precision, tc.want, tc.dur, got)
+		}
+	}
+
+	format, _ := ParseDurationFormat("ms", ".1")
+	if got := format.FormatSigned(-1500 * time.Microsecond); got != "-1.5ms" {
+		t.Fatalf("expected -1.5ms, got %s", got)
+	}
+
+	for _, tc := range []struct{ unit, precision string }{
+		{unit: "h", precision: ""},
+		{unit: "", precision: "0"},
+		{unit: "", precision: "two"},
+		{unit: "", precision: ".-1"},
+		{unit: "", precision: "19"},
+	} {
+		opts := defaultOptions()
+		opts.DurationUnit, opts.DurationPrecision = tc.unit, tc.precision
+
+		err := opts.Validate()
+		if !errors.Is(err, ErrInvalidDurationFormat) {
+			t.Fatalf("unit %q precision %q: expected ErrInvalidDurationFormat, got %v", tc.unit, tc.precision, err)
+		}
+	}
+}
+
+func TestBuildReport_DurationFormat(t *testing.T) {
+	t.Parallel()
+
+	versions := []string{"1.0.0-rc1", "2.0.0-rc1"}
+	slow := newUnit("1.0.0-rc1", makeTest("testPay", "CartTest", junit.StatusPassed, 77_912*time.Millisecond))
+	slow.Push("2.0.0-rc1", makeTest("testPay", "CartTest", junit.StatusPassed, 489_312*time.Microsecond))
+
+	opts := defaultOptions()
+	opts.DurationUnit, opts.DurationPrecision, opts.AlignRight = "s", ".2", true
+
+	report := buildReport(map[string]*unit{slow.FullName(): &slow}, versions, nil, opts)
+
+	if got := strings.Join(report.Texts()[0], ","); got != "Cart:Pay,77.91s,0.49s" {
+		t.Fatalf("unexpected cells: %s", got)
+	}
+
+	if data := buildTemplateData(report); data.Units[0].Cells[1].Text != "0.49s" {
+		t.Fatalf("expected template cells in the fixed unit, got %+v", data.Units[0].Cells)
+	}
+
+	for format, want := range map[string]string{
+		"table": "| Cart:Pay |    77.91s |     0.49s |",
+		"rst":   "| Cart:Pay |    77.91s |     0.49s |",
+		"csv":   "Cart:Pay,77.91s,0.49s",
+	} {
+		var out bytes.Buffer
+
+		err := Render(&out, report, format)
+		if err != nil {
+			t.Fatalf("Render %s failed: %v", format, err)
+		}
+
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in %s:\n%s", want, format, out.String())
+		}
+	}
+}
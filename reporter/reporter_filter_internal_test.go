@@ -0,0 +1,128 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+func TestParseFilter(t *testing.T) {
+	t.Parallel()
+
+	pay := makeTest("testPay with data set #1", "com.acme.cart.CartTest", junit.StatusPassed, time.Second)
+	free := makeTest("testPayFree", "com.acme.cart.CartTest", junit.StatusPassed, time.Second)
+
+	cases := []struct {
+		spec      string
+		pay, free bool
+	}{
+		{spec: "Cart:Pay", pay: true, free: false},
+		{spec: "name:Cart:*", pay: true, free: true},
+		{spec: "class:Cart", pay: true, free: true},
+		{spec: "class:CartTest", pay: true, free: true},
+		{spec: "class:Gift*", pay: false, free: false},
+		{spec: "method:testPay", pay: true, free: false},
+		{spec: "method:Pay*", pay: true, free: true},
+		{spec: "namespace:com.acme.*", pay: true, free: true},
+		{spec: "namespace:com.acme", pay: false, free: false},
+		{spec: "/Free$/", pay: false, free: true},
+		{spec: "method:/^test/", pay: true, free: true},
+	}
+
+	for _, tc := range cases {
+		filter, err := ParseFilter(tc.spec, false)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q) failed: %v", tc.spec, err)
+		}
+
+		if filter.matches(pay) != tc.pay || filter.matches(free) != tc.free {
+			t.Fatalf("%q: expected pay=%v free=%v", tc.spec, tc.pay, tc.free)
+		}
+	}
+
+	for _, spec := range []string{"/Cart[/", "class:[", "namespace:/(/"} {
+		_, err := ParseFilter(spec, true)
+		if !errors.Is(err, ErrInvalidFilter) {
+			t.Fatalf("ParseFilter(%q): expected ErrInvalidFilter, got %v", spec, err)
+		}
+	}
+}
+
+func TestAggregate_Filters(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeDiffReport(t, dir, "junit-1.0.0.xml",
+		`<testcase classname="a.CartTest" name="testPay" time="1"/>`,
+		`<testcase classname="a.CartTest" name="testPayFree" time="1"/>`,
+		`<testcase classname="a.GiftTest" name="testGift" time="1"/>`,
+		`<testcase classname="b.SoloTest" name="testDeposit" time="1"/>`,
+		`<testcase classname="c.SoloTest" name="testDeposit" time="1"/>`)
+
+	data, err := Load(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	filters, err := ParseFilters([]string{"class:Cart", "namespace:b"}, []string{"*Free"})
+	if err != nil {
+		t.Fatalf("ParseFilters failed: %v", err)
+	}
+
+	opts := defaultOptions()
+	opts.Directory = dir
+	opts.Filters = filters
+
+	report := Aggregate(data, opts)
+
+	var names []string
+	for _, row := range report.Texts() {
+		names = append(names, row[0])
+	}
+
+	if strings.Join(names, ",") != "Cart:Pay,Solo:Deposit" || report.Filtered != 2 {
+		t.Fatalf("unexpected rows %v with %d filtered", names, report.Filtered)
+	}
+
+	var out bytes.Buffer
+
+	err = Render(&out, report, "table")
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if !strings.HasSuffix(out.String(), "\nFiltered out 2 of 4 units.\n") {
+		t.Fatalf("expected the filter summary after the table:\n%s", out.String())
+	}
+}
+
+func TestLoadConfig_Filters(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := LoadConfig(writeConfig(t, `
+profiles:
+  cart:
+    include: ["class:Cart"]
+    exclude: ["/Free$/"]
+  broken:
+    include: ["/[/"]
+`))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	opts, err := cfg.Options("cart")
+	if err != nil || len(opts.Filters) != 2 || !opts.Filters[1].Exclude || opts.Filters[1].String() != "name:/Free$/" {
+		t.Fatalf("unexpected filters (%v): %+v", err, opts.Filters)
+	}
+
+	_, err = cfg.Options("broken")
+	if !errors.Is(err, ErrInvalidFilter) {
+		t.Fatalf("expected ErrInvalidFilter, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistory_RecordAndQuery(t *testing.T) {
+	t.Parallel()
+
+	data, err := Load(context.Background(), filepath.Join("..", "build"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	opts := defaultOptions()
+	opts.Group = true
+	opts.Major = true
+
+	history := OpenHistory(filepath.Join(t.TempDir(), "history"))
+
+	records, err := history.Query(HistoryQuery{Name: "", Version: "", Commit: "", Since: time.Time{}, Until: time.Time{}, Last: 0})
+	if err != nil || len(records) != 0 {
+		t.Fatalf("expected an empty store, got %v, %v", records, err)
+	}
+
+	first := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	report := Aggregate(data, opts)
+
+	// record out of order: queries sort runs by time
+	for i, commit := range []string{"c3", "c1", "c2"} {
+		n, err := history.Record(report, commit, first.Add(time.Duration(2-i)*time.Hour))
+		if err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+
+		if n != len(report.Stats()) {
+			t.Fatalf("expected %d records, got %d", len(report.Stats()), n)
+		}
+	}
+
+	records, err = history.Query(HistoryQuery{Name: "Cart:Pay", Version: "7.x", Commit: "", Since: time.Time{}, Until: time.Time{}, Last: 0})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	if len(records) != 3 || records[0].Commit != "c2" || records[2].Commit != "c3" {
+		t.Fatalf("expected three runs in time order, got %+v", records)
+	}
+
+	if records[0].Passed != 100 || records[0].Mean == 0 || !records[0].Time.Equal(first) {
+		t.Fatalf("unexpected record: %+v", records[0])
+	}
+
+	records, err = history.Query(HistoryQuery{
+		Name: "Cart:*", Version: "", Commit: "", Since: first.Add(time.Hour), Until: time.Time{}, Last: 1,
+	})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	for _, record := range records {
+		if record.Commit != "c3" || !strings.HasPrefix(record.Name, "Cart:") {
+			t.Fatalf("expected only Cart units of the last run, got %+v", record)
+		}
+	}
+
+	var (
+		out       bytes.Buffer
+		durations DurationFormat
+	)
+
+	err = RenderHistory(&out, records, "table", durations)
+	if err != nil {
+		t.Fatalf("RenderHistory failed: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "| 2024-05-01T12:00:00Z | c3") {
+		t.Fatalf("unexpected history table:\n%s", out.String())
+	}
+}
+
+func TestLastRuns(t *testing.T) {
+	t.Parallel()
+
+	at := func(hour int) HistoryRecord {
+		return HistoryRecord{
+			Time: time.Date(2024, 1, 1, hour, 0, 0, 0, time.UTC), Commit: "", Version: "", Name: "",
+			Sum: 0, Mean: 0, Median: 0, Min: 0, Max: 0, Passed: 0, Failed: 0, Skipped: 0, Errors: 0,
+		}
+	}
+
+	records := []HistoryRecord{at(1), at(1), at(2), at(3), at(3)}
+
+	if got := lastRuns(records, 2); len(got) != 3 || got[0].Time.Hour() != 2 {
+		t.Fatalf("expected the records of the last two runs, got %+v", got)
+	}
+
+	if got := lastRuns(records, 5); len(got) != len(records) {
+		t.Fatalf("expected all records, got %d", len(got))
+	}
+}
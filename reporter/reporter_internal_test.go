@@ -10,7 +10,7 @@ import (
 
 func baselinePath(name string) string {
 	// relative from package dir -> project root
-	return filepath.Join("..", "..", "build", "runs", name)
+	return filepath.Join("..", "build", "runs", name)
 }
 
 func readBaseline(t *testing.T, name string) string {
@@ -26,9 +26,9 @@ func readBaseline(t *testing.T, name string) string {
 
 func runAndCapture(opts Options) string {
 	var buf bytes.Buffer
-	// tests run from package dir (internal/reporter), make directory point to project build
+	// tests run from the package dir (reporter), make directory point to the project build
 	if opts.Directory == "" || opts.Directory == "./build" {
-		opts.Directory = filepath.Join("..", "..", "build")
+		opts.Directory = filepath.Join("..", "build")
 	}
 
 	_ = Run(&buf, opts) // errors are surfaced by tests comparing output
@@ -39,16 +39,10 @@ func runAndCapture(opts Options) string {
 func TestRun_DefaultMatchesBaseline(t *testing.T) {
 	t.Parallel()
 
-	got := runAndCapture(Options{
-		Directory:    "./build",
-		Ticks:        false,
-		Group:        false,
-		Major:        false,
-		Median:       false,
-		Rotate:       false,
-		OutputFormat: "",
-		OutputFile:   "",
-	})
+	opts := defaultOptions()
+	opts.Directory = "./build"
+
+	got := runAndCapture(opts)
 
 	want := readBaseline(t, "run-default.txt")
 
@@ -60,16 +54,11 @@ func TestRun_DefaultMatchesBaseline(t *testing.T) {
 func TestRun_TicksMatchesBaseline(t *testing.T) {
 	t.Parallel()
 
-	got := runAndCapture(Options{
-		Directory:    "./build",
-		Ticks:        true,
-		Group:        false,
-		Major:        false,
-		Median:       false,
-		Rotate:       false,
-		OutputFormat: "",
-		OutputFile:   "",
-	})
+	opts := defaultOptions()
+	opts.Directory = "./build"
+	opts.Ticks = true
+
+	got := runAndCapture(opts)
 
 	want := readBaseline(t, "run-ticks.txt")
 
@@ -81,16 +70,11 @@ func TestRun_TicksMatchesBaseline(t *testing.T) {
 func TestRun_RotateMatchesBaseline(t *testing.T) {
 	t.Parallel()
 
-	got := runAndCapture(Options{
-		Directory:    "./build",
-		Ticks:        false,
-		Group:        false,
-		Major:        false,
-		Median:       false,
-		Rotate:       true,
-		OutputFormat: "",
-		OutputFile:   "",
-	})
+	opts := defaultOptions()
+	opts.Directory = "./build"
+	opts.Rotate = true
+
+	got := runAndCapture(opts)
 
 	want := readBaseline(t, "run-rotate.txt")
 
@@ -102,16 +86,11 @@ func TestRun_RotateMatchesBaseline(t *testing.T) {
 func TestRun_GroupMatchesBaseline(t *testing.T) {
 	t.Parallel()
 
-	got := runAndCapture(Options{
-		Directory:    "./build",
-		Ticks:        false,
-		Group:        true,
-		Major:        false,
-		Median:       false,
-		Rotate:       false,
-		OutputFormat: "",
-		OutputFile:   "",
-	})
+	opts := defaultOptions()
+	opts.Directory = "./build"
+	opts.Group = true
+
+	got := runAndCapture(opts)
 
 	want := readBaseline(t, "run-group.txt")
 
@@ -123,16 +102,12 @@ func TestRun_GroupMatchesBaseline(t *testing.T) {
 func TestRun_GroupMajorMatchesBaseline(t *testing.T) {
 	t.Parallel()
 
-	got := runAndCapture(Options{
-		Directory:    "./build",
-		Ticks:        false,
-		Group:        true,
-		Major:        true,
-		Median:       false,
-		Rotate:       false,
-		OutputFormat: "",
-		OutputFile:   "",
-	})
+	opts := defaultOptions()
+	opts.Directory = "./build"
+	opts.Group = true
+	opts.Major = true
+
+	got := runAndCapture(opts)
 
 	want := readBaseline(t, "run-group-major.txt")
 
@@ -144,16 +119,11 @@ func TestRun_GroupMajorMatchesBaseline(t *testing.T) {
 func TestRun_MedianMatchesBaseline(t *testing.T) {
 	t.Parallel()
 
-	got := runAndCapture(Options{
-		Directory:    "./build",
-		Ticks:        false,
-		Group:        false,
-		Major:        false,
-		Median:       true,
-		Rotate:       false,
-		OutputFormat: "",
-		OutputFile:   "",
-	})
+	opts := defaultOptions()
+	opts.Directory = "./build"
+	opts.Median = true
+
+	got := runAndCapture(opts)
 
 	want := readBaseline(t, "run-median.txt")
 
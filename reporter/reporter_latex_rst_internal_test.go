@@ -0,0 +1,76 @@
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runExport(t *testing.T, format string) string {
+	t.Helper()
+
+	out := filepath.Join(t.TempDir(), "out."+format)
+	opts := defaultOptions()
+	opts.Directory = filepath.Join("..", "build")
+	opts.OutputFormat = format
+	opts.OutputFile = out
+
+	var b strings.Builder
+
+	err := Run(&b, opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read %s export: %v", format, err)
+	}
+
+	return string(data)
+}
+
+func TestRun_ExportLatexBoldsBestPerRow(t *testing.T) {
+	t.Parallel()
+
+	got := runExport(t, "latex")
+
+	for _, want := range []string{
+		"\\begin{tabular}{lrrrrrrrrr}\n\\toprule\n",
+		`Cart:Pay & 29.7s & 29.1s & 29.7s & 48.2s & \textbf{15.7s} & 17.2s & 26.7s & 27.2s & 27.2s \\`,
+		"\\bottomrule\n\\end{tabular}\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("latex output does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRun_ExportRSTGridAndList(t *testing.T) {
+	t.Parallel()
+
+	grid := runExport(t, "rst")
+	if !strings.Contains(grid, "| Cart:Pay                   | 29.7s | 29.1s | 29.7s         | 48.2s |") {
+		t.Fatalf("unexpected rst grid table:\n%s", grid)
+	}
+
+	if !strings.Contains(grid, "| Cart:EagerLoaderPay        | \\-    |") {
+		t.Fatalf("dash cells must be escaped:\n%s", grid)
+	}
+
+	list := runExport(t, "rst-list")
+	if !strings.HasPrefix(list, ".. list-table::\n   :header-rows: 1\n") ||
+		!strings.Contains(list, "   * - Cart:Pay\n     - 29.7s\n") {
+		t.Fatalf("unexpected rst list table:\n%s", list)
+	}
+}
+
+func TestLatexEscape(t *testing.T) {
+	t.Parallel()
+
+	got := newLatexEscaper().Replace(`a_b & 50% 10µs`)
+	if got != `a\_b \& 50\% 10\textmu{}s` {
+		t.Fatalf("unexpected escape: %s", got)
+	}
+}
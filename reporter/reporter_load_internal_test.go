@@ -0,0 +1,282 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad_FilesAndDirectories(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join("..", "build", "junit-7.0.0.xml")
+
+	data, err := Load(context.Background(), file, filepath.Join("..", "build"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	files := data.Files()
+	if len(files) != 10 || files[0] != file {
+		t.Fatalf("unexpected files: %v", files)
+	}
+}
+
+func TestLoad_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Load(ctx, filepath.Join("..", "build"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRunContext_Progress(t *testing.T) {
+	t.Parallel()
+
+	var calls []Progress
+
+	opts := defaultOptions()
+	opts.Directory = filepath.Join("..", "build")
+	opts.Progress = func(p Progress) {
+		calls = append(calls, p)
+	}
+
+	var b strings.Builder
+
+	err := RunContext(context.Background(), &b, opts)
+	if err != nil {
+		t.Fatalf("RunContext failed: %v", err)
+	}
+
+	if len(calls) != 9 {
+		t.Fatalf("expected a progress call per file, got %d", len(calls))
+	}
+
+	last := calls[len(calls)-1]
+	if last.Files != 9 || last.TotalFiles != 9 || last.Tests != 6377 {
+		t.Fatalf("unexpected final progress: %+v", last)
+	}
+}
+
+func TestLoader_ParallelMatchesSequential(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join("..", "build")
+
+	sequential, err := Loader{Jobs: 1, Progress: nil, Stdin: nil, KeepGoing: false, Cache: nil}.Load(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("sequential Load failed: %v", err)
+	}
+
+	parallel, err := Loader{Jobs: 8, Progress: nil, Stdin: nil, KeepGoing: false, Cache: nil}.Load(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("parallel Load failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(sequential.files, parallel.files) {
+		t.Fatalf("parallel dataset differs from sequential: %v vs %v", parallel.Files(), sequential.Files())
+	}
+}
+
+func TestLoader_FirstErrorInOrder(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "junit-1.0.0.xml")
+
+	for _, name := range []string{"junit-1.0.0.xml", "junit-2.0.0.xml"} {
+		err := os.WriteFile(filepath.Join(dir, name), []byte("<testsuite"), 0o600)
+		if err != nil {
+			t.Fatalf("write fixture: %v", err)
+		}
+	}
+
+	_, err := Loader{Jobs: 2, Progress: nil, Stdin: nil, KeepGoing: false, Cache: nil}.Load(context.Background(), dir)
+	if err == nil || !strings.Contains(err.Error(), broken) {
+		t.Fatalf("expected error for %s, got %v", broken, err)
+	}
+}
+
+func TestLoader_ErrorNotHiddenByCancel(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "junit-2.0.0.xml")
+
+	err := os.WriteFile(broken, []byte("<testsuite"), 0o600)
+	if err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	// a valid report on stdin that only ends when loading is canceled
+	stdin, writer := io.Pipe()
+	defer stdin.Close()
+
+	go func() {
+		_, err := io.WriteString(writer, "<testsuite>")
+		for err == nil {
+			_, err = io.WriteString(writer, `<testcase classname="a.CartTest" name="testPay" time="1"/>`)
+		}
+	}()
+
+	loader := Loader{Jobs: 2, Progress: nil, Stdin: stdin, KeepGoing: false, Cache: nil}
+
+	_, err = loader.Load(context.Background(), "1.0.0=-", broken)
+	if err == nil || !strings.Contains(err.Error(), broken) || errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the error of %s, got %v", broken, err)
+	}
+}
+
+func BenchmarkLoad(b *testing.B) {
+	dir := filepath.Join("..", "build")
+
+	for _, jobs := range []int{1, 0} {
+		b.Run(fmt.Sprintf("jobs=%d", jobs), func(b *testing.B) {
+			for b.Loop() {
+				_, err := Loader{Jobs: jobs, Progress: nil, Stdin: nil, KeepGoing: false, Cache: nil}.Load(context.Background(), dir)
+				if err != nil {
+					b.Fatalf("Load failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoader_StdinAndLabels(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("..", "build", "junit-7.0.0.xml"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	loader := Loader{Jobs: 0, Progress: nil, Stdin: bytes.NewReader(data), KeepGoing: false, Cache: nil}
+
+	loaded, err := loader.Load(context.Background(), "nightly=-", "6.x="+filepath.Join("..", "build", "junit-6.0.4.xml"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if files := loaded.Files(); len(files) != 2 || files[0] != "-" {
+		t.Fatalf("unexpected files: %v", files)
+	}
+
+	if got := loaded.files[0]; got.Label != "nightly" || len(got.Tests) != 826 {
+		t.Fatalf("unexpected stdin report: label %q, %d tests", got.Label, len(got.Tests))
+	}
+
+	if got := loaded.files[1].version(true, true); got != "6.x" {
+		t.Fatalf("expected the explicit label to be grouped, got %q", got)
+	}
+
+	if got := loaded.files[0].version(true, true); got != "nightly" {
+		t.Fatalf("expected a non-version label to be kept, got %q", got)
+	}
+
+	_, err = loader.Load(context.Background(), "-")
+	if !errors.Is(err, ErrMissingLabel) {
+		t.Fatalf("expected ErrMissingLabel, got %v", err)
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]input{
+		"build":                   {path: "build", label: ""},
+		"7.0=build/junit-7.xml":   {path: "build/junit-7.xml", label: "7.0"},
+		"dir/a=b/junit-1.0.0.xml": {path: "dir/a=b/junit-1.0.0.xml", label: ""},
+		"=build":                  {path: "=build", label: ""},
+		"nightly=-":               {path: "-", label: "nightly"},
+	}
+
+	for spec, want := range cases {
+		if got := parseSource(spec); got != want {
+			t.Fatalf("parseSource(%q) = %+v, want %+v", spec, got, want)
+		}
+	}
+}
+
+func TestLoader_KeepGoing(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	fixture, err := os.ReadFile(filepath.Join("..", "build", "junit-7.0.0.xml"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	files := map[string]string{
+		"junit-1.0.0.xml": "<testsuites>\n  <testsuite>\n    <testcase name=\"a\">\n",
+		"junit-2.0.0.xml": string(fixture),
+		"junit-3.0.0.xml": "<testsuite><testcase></testsuite>",
+	}
+
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("write fixture: %v", err)
+		}
+	}
+
+	loaded, err := Loader{Jobs: 0, Progress: nil, Stdin: nil, KeepGoing: true, Cache: nil}.Load(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got := loaded.Files(); len(got) != 1 || got[0] != filepath.Join(dir, "junit-2.0.0.xml") {
+		t.Fatalf("expected only the valid report, got %v", got)
+	}
+
+	skipped := loaded.Errors()
+	if len(skipped) != 2 {
+		t.Fatalf("expected 2 skipped reports, got %v", skipped)
+	}
+
+	first := skipped[0]
+	if first.Path != filepath.Join(dir, "junit-1.0.0.xml") || first.Line != 4 || first.Column != 1 {
+		t.Fatalf("unexpected first error: %+v", first)
+	}
+
+	if skipped[1].Path != filepath.Join(dir, "junit-3.0.0.xml") || skipped[1].Line != 1 {
+		t.Fatalf("unexpected second error: %+v", skipped[1])
+	}
+
+	opts := defaultOptions()
+	opts.Directory = dir
+	opts.KeepGoing = true
+
+	var b strings.Builder
+
+	err = RunContext(context.Background(), &b, opts)
+
+	var skippedErr *SkippedReportsError
+	if !errors.As(err, &skippedErr) || len(skippedErr.Errors) != 2 {
+		t.Fatalf("expected SkippedReportsError with 2 reports, got %v", err)
+	}
+
+	if !strings.Contains(b.String(), "2.0.0") {
+		t.Fatalf("expected the report to be rendered, got:\n%s", b.String())
+	}
+
+	opts.KeepGoing = false
+
+	err = RunContext(context.Background(), &b, opts)
+
+	var reportErr *ReportError
+	if !errors.As(err, &reportErr) || reportErr.Path != first.Path {
+		t.Fatalf("expected strict mode to fail on the first report, got %v", err)
+	}
+}
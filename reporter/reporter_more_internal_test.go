@@ -59,16 +59,8 @@ func TestFormatDurationReadable(t *testing.T) {
 func TestRun_NoFilesError(t *testing.T) {
 	t.Parallel()
 	// point to a non-existent folder
-	errDir := Options{
-		Directory:    "./nonexistent-folder",
-		Ticks:        false,
-		Group:        false,
-		Major:        false,
-		Median:       false,
-		Rotate:       false,
-		OutputFormat: "",
-		OutputFile:   "",
-	}
+	errDir := defaultOptions()
+	errDir.Directory = "./nonexistent-folder"
 
 	var buf strings.Builder
 
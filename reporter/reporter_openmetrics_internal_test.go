@@ -0,0 +1,69 @@
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+func TestRun_ExportOpenMetrics(t *testing.T) {
+	t.Parallel()
+	td := t.TempDir()
+	out := filepath.Join(td, "out.prom")
+	opts := defaultOptions()
+	opts.Directory = filepath.Join("..", "build")
+	opts.OutputFormat = "openmetrics"
+	opts.OutputFile = out
+
+	var b strings.Builder
+
+	err := Run(&b, opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read out prom: %v", err)
+	}
+
+	want := `junit_test_duration_seconds{class="Cart",method="Pay",version="7.1.0",stat="median"} 0.659987`
+	if !strings.Contains(string(data), want) {
+		t.Fatalf("openmetrics output does not contain %q", want)
+	}
+
+	if !strings.HasSuffix(string(data), "# EOF\n") {
+		t.Fatalf("openmetrics output must end with # EOF")
+	}
+}
+
+func TestWriteOpenMetrics_ResultsAndEscaping(t *testing.T) {
+	t.Parallel()
+
+	unitVal := newUnit("1.0", makeTest("testPay", "pkg.Cart\"Test", junit.StatusPassed, 1500*time.Millisecond))
+	unitVal.Push("1.0", makeTest("testPay", "pkg.Cart\"Test", junit.StatusFailed, 0))
+	unitVal.Push("1.0", makeTest("testPay", "pkg.Cart\"Test", junit.StatusSkipped, 0))
+
+	var b strings.Builder
+
+	err := writeOpenMetrics(&b, []UnitStats{unitVal.Aggregate("1.0")})
+	if err != nil {
+		t.Fatalf("writeOpenMetrics failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"# TYPE junit_test_results gauge\n",
+		`junit_test_duration_seconds{class="Cart\"",method="Pay",version="1.0",stat="sum"} 1.5`,
+		`junit_test_results{class="Cart\"",method="Pay",version="1.0",status="passed"} 1`,
+		`junit_test_results{class="Cart\"",method="Pay",version="1.0",status="failed"} 1`,
+		`junit_test_results{class="Cart\"",method="Pay",version="1.0",status="skipped"} 1`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Fatalf("output does not contain %q:\n%s", want, b.String())
+		}
+	}
+}
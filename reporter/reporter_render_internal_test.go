@@ -0,0 +1,102 @@
+package reporter
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun_MultipleOutputsInOnePass(t *testing.T) {
+	t.Parallel()
+	td := t.TempDir()
+	csvPath := filepath.Join(td, "out.csv")
+	promPath := filepath.Join(td, "out.prom")
+	opts := defaultOptions()
+	opts.Directory = filepath.Join("..", "build")
+	opts.Outputs = []Output{
+		ParseOutput("csv=" + csvPath),
+		ParseOutput("openmetrics=" + promPath),
+		ParseOutput("rst"),
+	}
+
+	var b strings.Builder
+
+	err := Run(&b, opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// rst targets the writer, so the table is not printed
+	if !strings.HasPrefix(b.String(), "+---") {
+		t.Fatalf("expected rst on the writer, got:\n%s", b.String())
+	}
+
+	for _, outPath := range []string{csvPath, promPath} {
+		_, err = os.Stat(outPath)
+		if err != nil {
+			t.Fatalf("output not created: %v", err)
+		}
+	}
+}
+
+func TestRun_UnknownOutputFormat(t *testing.T) {
+	t.Parallel()
+	opts := defaultOptions()
+	opts.Directory = filepath.Join("..", "build")
+	opts.Outputs = []Output{ParseOutput("table"), ParseOutput("docx")}
+
+	var b strings.Builder
+
+	err := Run(&b, opts)
+	if !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected ErrUnsupportedFormat, got %v", err)
+	}
+
+	if b.Len() != 0 {
+		t.Fatalf("nothing must be written before formats are validated")
+	}
+}
+
+func TestBuildReportCellStatuses(t *testing.T) {
+	t.Parallel()
+
+	passed := newUnit("1.0", makeTest("testA", "pkg.ATest", "passed", time.Second))
+	failed := newUnit("1.0", makeTest("testB", "pkg.BTest", "failed", time.Second))
+	units := map[string]*unit{passed.FullName(): &passed, failed.FullName(): &failed}
+
+	report := buildReport(units, []string{"1.0", "2.0"}, nil, defaultOptions())
+
+	want := [][]CellStatus{
+		{CellLabel, CellPassed, CellMissing},
+		{CellLabel, CellFailed, CellMissing},
+	}
+
+	for i, row := range report.Rows {
+		for j, cell := range row {
+			if cell.Status != want[i][j] {
+				t.Fatalf("cell %d/%d: got status %s; want %s", i, j, cell.Status, want[i][j])
+			}
+		}
+	}
+
+	if report.Rows[0][1].Value != time.Second || !report.Rows[0][1].OK() {
+		t.Fatalf("unexpected passed cell: %+v", report.Rows[0][1])
+	}
+
+	var renderer Renderer = RendererFunc(func(w io.Writer, r *Report) error {
+		_, err := io.WriteString(w, strings.Join(r.Columns, ","))
+
+		return err
+	})
+
+	var b strings.Builder
+
+	err := renderer.Render(&b, report)
+	if err != nil || b.String() != "Name,1.0,2.0" {
+		t.Fatalf("unexpected render result %q: %v", b.String(), err)
+	}
+}
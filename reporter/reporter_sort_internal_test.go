@@ -0,0 +1,170 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+func sortOptions(sortBy, order string, top int) Options {
+	opts := defaultOptions()
+	opts.SortBy = sortBy
+	opts.SortOrder = order
+	opts.Top = top
+
+	return opts
+}
+
+func TestBuildReport_Sort(t *testing.T) {
+	t.Parallel()
+
+	versions := []string{"1.0.0", "2.0.0", "3.0.0"}
+	units := map[string]*unit{}
+
+	// a negative duration is a failed sample, zero means no sample
+	push := func(class string, durations ...int) {
+		for idx, ms := range durations {
+			status := junit.StatusPassed
+			if ms < 0 {
+				status = junit.StatusFailed
+			}
+
+			if ms == 0 {
+				continue
+			}
+
+			test := makeTest("testRun", "pkg."+class, status, time.Duration(ms)*time.Millisecond)
+
+			if unitVal, ok := units[class+":Run"]; ok {
+				unitVal.Push(versions[idx], test)
+
+				continue
+			}
+
+			unitVal := newUnit(versions[idx], test)
+			units[unitVal.FullName()] = &unitVal
+		}
+	}
+
+	push("Alpha", 100, 100, 400)
+	push("Beta", 300, 200, 100)
+	push("Gamma", 200, 900, 250)
+	push("Delta", -1, -1, 200)
+	push("Omega", 50, 60, 0)
+
+	cases := []struct {
+		sortBy, order string
+		want          string
+	}{
+		{sortBy: "", order: "", want: "Alpha,Beta,Delta,Gamma,Omega"},
+		{sortBy: "name", order: "desc", want: "Omega,Gamma,Delta,Beta,Alpha"},
+		{sortBy: "duration", order: "", want: "Alpha,Gamma,Delta,Beta,Omega"},
+		{sortBy: "duration:2.0.0", order: "asc", want: "Omega,Alpha,Beta,Gamma,Delta"},
+		{sortBy: "delta", order: "", want: "Alpha,Beta,Gamma,Delta,Omega"},
+		{sortBy: "delta:1.0.0..2.0.0", order: "", want: "Gamma,Omega,Alpha,Beta,Delta"},
+		{sortBy: "variance", order: "", want: "Gamma,Alpha,Beta,Omega,Delta"},
+		{sortBy: "failures", order: "", want: "Delta,Alpha,Beta,Gamma,Omega"},
+	}
+
+	for _, tc := range cases {
+		report := buildReport(units, versions, nil, sortOptions(tc.sortBy, tc.order, 0))
+
+		var names []string
+		for _, row := range report.Texts() {
+			names = append(names, strings.TrimSuffix(row[0], ":Run"))
+		}
+
+		if got := strings.Join(names, ","); got != tc.want {
+			t.Fatalf("sort %q %q: expected %s, got %s", tc.sortBy, tc.order, tc.want, got)
+		}
+	}
+
+	report := buildReport(units, versions, nil, sortOptions("delta:1.0.0..3.0.0", "", 2))
+	if report.Omitted != 3 || len(report.Stats()) != 2*len(versions) || report.Columns[0] != "Name" {
+		t.Fatalf("expected the top 2 units only, got %d omitted and %+v", report.Omitted, report.Texts())
+	}
+
+	if data := buildTemplateData(report); data.Units[0].Name != "Alpha:Run" || data.Units[1].Name != "Gamma:Run" {
+		t.Fatalf("expected template units in sort order, got %+v", data.Units)
+	}
+
+	var out bytes.Buffer
+
+	err := Render(&out, report, "table")
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if !strings.HasSuffix(out.String(), "\nShowing the top 2 of 5 units.\n") {
+		t.Fatalf("expected the top summary after the table:\n%s", out.String())
+	}
+}
+
+func TestOptionsValidate_Sort(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct{ sortBy, order string }{
+		{sortBy: "speed", order: ""},
+		{sortBy: "name:1.0", order: ""},
+		{sortBy: "duration:", order: ""},
+		{sortBy: "delta:1.0", order: ""},
+		{sortBy: "delta:..2.0", order: ""},
+		{sortBy: "failures", order: "up"},
+	} {
+		err := sortOptions(tc.sortBy, tc.order, 0).Validate()
+		if !errors.Is(err, ErrInvalidSort) {
+			t.Fatalf("sort %q %q: expected ErrInvalidSort, got %v", tc.sortBy, tc.order, err)
+		}
+	}
+
+	err := sortOptions("delta:1.0..2.0", "asc", 3).Validate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestOptionsValidate_Top(t *testing.T) {
+	t.Parallel()
+
+	err := sortOptions("", "", -1).Validate()
+	if !errors.Is(err, ErrInvalidTop) {
+		t.Fatalf("expected ErrInvalidTop, got %v", err)
+	}
+}
+
+func TestOptionsValidateSort(t *testing.T) {
+	t.Parallel()
+
+	versions := []string{"1.0", "2.0"}
+
+	for _, sortBy := range []string{"", "name", "duration", "duration:2.0", "delta:1.0..2.0"} {
+		err := sortOptions(sortBy, "", 0).ValidateSort(versions)
+		if err != nil {
+			t.Fatalf("sort %q: unexpected error: %v", sortBy, err)
+		}
+	}
+
+	for _, sortBy := range []string{"duration:3.0", "delta:1.0..3.0", "delta:0.9..2.0"} {
+		err := sortOptions(sortBy, "", 0).ValidateSort(versions)
+		if !errors.Is(err, ErrInvalidSort) || !errors.Is(err, ErrUnknownVersion) {
+			t.Fatalf("sort %q: expected ErrInvalidSort and ErrUnknownVersion, got %v", sortBy, err)
+		}
+	}
+
+	opts := sortOptions("duration:6.2.4", "", 0)
+	opts.Directory = filepath.Join("..", "build")
+	opts.VersionConstraint = ">= 7"
+
+	var out bytes.Buffer
+
+	err := RunContext(context.Background(), &out, opts)
+	if !errors.Is(err, ErrUnknownVersion) || out.Len() != 0 {
+		t.Fatalf("expected RunContext to reject a version left out by the constraint, got %v:\n%s", err, out.String())
+	}
+}
@@ -0,0 +1,205 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+type testKey struct {
+	Classname string
+	Name      string
+	Duration  time.Duration
+	Status    junit.Status
+}
+
+func sortedTestKeys(tests []junit.Test) []testKey {
+	keys := make([]testKey, 0, len(tests))
+	for _, test := range tests {
+		keys = append(keys, testKey{Classname: test.Classname, Name: test.Name, Duration: test.Duration, Status: test.Status})
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.Classname != b.Classname {
+			return a.Classname < b.Classname
+		}
+
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+
+		if a.Duration != b.Duration {
+			return a.Duration < b.Duration
+		}
+
+		return a.Status < b.Status
+	})
+
+	return keys
+}
+
+func TestDecodeReport_MatchesIngest(t *testing.T) {
+	t.Parallel()
+
+	files, err := discoverJUnitFiles(filepath.Join("..", "build"))
+	if err != nil {
+		t.Fatalf("discover failed: %v", err)
+	}
+
+	for _, path := range files {
+		suites, err := junit.IngestFile(path)
+		if err != nil {
+			t.Fatalf("IngestFile failed: %v", err)
+		}
+
+		var want []junit.Test
+		for _, suite := range suites {
+			want = append(want, depthSuite(suite)...)
+		}
+
+		got, err := loadFile(t.Context(), path)
+		if err != nil {
+			t.Fatalf("loadFile failed: %v", err)
+		}
+
+		wantKeys, gotKeys := sortedTestKeys(want), sortedTestKeys(got.Tests)
+		if len(wantKeys) != len(gotKeys) {
+			t.Fatalf("%s: expected %d tests, got %d", path, len(wantKeys), len(gotKeys))
+		}
+
+		for i := range wantKeys {
+			if wantKeys[i] != gotKeys[i] {
+				t.Fatalf("%s: test %d differs: %+v vs %+v", path, i, gotKeys[i], wantKeys[i])
+			}
+		}
+
+		if !got.Time.Equal(suitesTimestamp(suites)) {
+			t.Fatalf("%s: timestamp %v, want %v", path, got.Time, suitesTimestamp(suites))
+		}
+	}
+}
+
+func TestDecodeReport_NestedStatusesAndProperties(t *testing.T) {
+	t.Parallel()
+
+	doc := `<?xml version="1.0"?>
+<testsuites>
+  <testsuite name="outer" timestamp="not a time">
+    <properties><property name="timestamp" value="2024-05-01T10:00:00Z"/></properties>
+    <testcase classname="a.B" name="one" time="1,000.5"><system-out>noise</system-out></testcase>
+    <testsuite name="inner" timestamp="2023-01-01T00:00:00Z">
+      <testcase classname="a.B" name="two" time="0.25"><skipped/></testcase>
+      <testcase classname="a.B" name="three" time="2s"><failure message="x">trace</failure></testcase>
+    </testsuite>
+    <testcase classname="a.B" name="four"><error type="E"/></testcase>
+  </testsuite>
+</testsuites>`
+
+	var tests []junit.Test
+
+	stamp, err := decodeReport(t.Context(), strings.NewReader(doc), func(test junit.Test) {
+		tests = append(tests, test)
+	})
+	if err != nil {
+		t.Fatalf("decodeReport failed: %v", err)
+	}
+
+	if want := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC); !stamp.Equal(want) {
+		t.Fatalf("expected timestamp %v, got %v", want, stamp)
+	}
+
+	want := []testKey{
+		{Classname: "a.B", Name: "one", Duration: 1000500 * time.Millisecond, Status: junit.StatusPassed},
+		{Classname: "a.B", Name: "two", Duration: 250 * time.Millisecond, Status: junit.StatusSkipped},
+		{Classname: "a.B", Name: "three", Duration: 2 * time.Second, Status: junit.StatusFailed},
+		{Classname: "a.B", Name: "four", Duration: 0, Status: junit.StatusError},
+	}
+
+	if len(tests) != len(want) {
+		t.Fatalf("expected %d tests, got %d", len(want), len(tests))
+	}
+
+	for i, test := range tests {
+		got := testKey{Classname: test.Classname, Name: test.Name, Duration: test.Duration, Status: test.Status}
+		if got != want[i] {
+			t.Fatalf("test %d: expected %+v, got %+v", i, want[i], got)
+		}
+	}
+}
+
+func TestDecodeReport_Malformed(t *testing.T) {
+	t.Parallel()
+
+	_, err := decodeReport(t.Context(), strings.NewReader("<testsuite><testcase>"), func(junit.Test) {})
+	if err == nil {
+		t.Fatal("expected an error for a truncated document")
+	}
+
+	for _, name := range []string{``, `name=" "`} {
+		doc := `<testsuite><testcase classname="a.CartTest" ` + name + ` time="1"/></testsuite>`
+
+		_, err = decodeReport(t.Context(), strings.NewReader(doc), func(junit.Test) {})
+
+		var reportErr *ReportError
+		if !errors.Is(err, ErrMissingTestName) || !errors.As(err, &reportErr) || reportErr.Line != 1 {
+			t.Fatalf("expected a positioned ErrMissingTestName for %q, got %v", doc, err)
+		}
+	}
+}
+
+func TestDecodeReport_Cancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	doc := `<testsuite><testcase name="testA" time="1"/><testcase name="testB" time="1"/></testsuite>`
+	visited := 0
+
+	_, err := decodeReport(ctx, strings.NewReader(doc), func(junit.Test) {
+		visited++
+
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) || visited != 1 {
+		t.Fatalf("expected decoding to stop after the first test, got %d tests and %v", visited, err)
+	}
+}
+
+func BenchmarkDecodeReport(b *testing.B) {
+	data, err := os.ReadFile(filepath.Join("..", "build", "junit-7.0.0.xml"))
+	if err != nil {
+		b.Fatalf("read fixture: %v", err)
+	}
+
+	b.Run("stream", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			_, err := decodeReport(b.Context(), bytes.NewReader(data), func(junit.Test) {})
+			if err != nil {
+				b.Fatalf("decodeReport failed: %v", err)
+			}
+		}
+	})
+
+	b.Run("ingest", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			_, err := junit.IngestReader(bytes.NewReader(data))
+			if err != nil {
+				b.Fatalf("IngestReader failed: %v", err)
+			}
+		}
+	})
+}
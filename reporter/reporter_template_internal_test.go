@@ -0,0 +1,57 @@
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runTemplate(t *testing.T, name, body string) string {
+	t.Helper()
+
+	tmplPath := filepath.Join(t.TempDir(), name)
+
+	err := os.WriteFile(tmplPath, []byte(body), 0o600)
+	if err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	opts := defaultOptions()
+	opts.Directory = filepath.Join("..", "build")
+	opts.Template = tmplPath
+
+	var b strings.Builder
+
+	err = Run(&b, opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	return b.String()
+}
+
+func TestRun_TextTemplate(t *testing.T) {
+	t.Parallel()
+
+	got := runTemplate(t, "report.tmpl", `{{ .Stat }} {{ len .Versions }}
+{{ range .Units }}{{ if eq .Name "Cart:Pay" }}`+
+		`{{ $old := .Cell "7.0.0" }}{{ $new := .Cell "7.1.0" }}`+
+		`{{ .Name }} {{ $old.Text }} {{ $new.Text }} {{ delta $old $new }} {{ percent $old $new }} `+
+		`{{ formatDuration $new.Median }} {{ (.Cell "1.0").Text }}{{ end }}{{ end }}`)
+
+	want := "sum 9\nCart:Pay 15.7s 17.2s +1.51s +9.6% 660ms -"
+	if got != want {
+		t.Fatalf("template output mismatch\n--- want\n%s\n--- got\n%s", want, got)
+	}
+}
+
+func TestRun_HTMLTemplateEscapes(t *testing.T) {
+	t.Parallel()
+
+	got := runTemplate(t, "report.html", `{{ range .Units }}{{ if eq .Name "Cart:Pay" }}<b>{{ "<x>" }}</b>{{ end }}{{ end }}`)
+
+	if got != "<b>&lt;x&gt;</b>" {
+		t.Fatalf("unexpected html output: %s", got)
+	}
+}
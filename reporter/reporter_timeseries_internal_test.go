@@ -0,0 +1,115 @@
+package reporter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const timestampedReport = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="Cart" timestamp="2024-05-01T10:00:00" tests="2">
+    <testcase name="testPay" classname="pkg.CartTest" time="0.5"/>
+    <testcase name="testPay" classname="pkg.CartTest" time="1.5"/>
+  </testsuite>
+</testsuites>
+`
+
+func writeTimestampedReport(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "junit-1.0.0 rc.xml"), []byte(timestampedReport), 0o600)
+	if err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+
+	return dir
+}
+
+func TestRun_ExportInflux(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTimestampedReport(t)
+	out := filepath.Join(dir, "out.lp")
+	opts := defaultOptions()
+	opts.Directory = dir
+	opts.Group = true
+	opts.OutputFormat = "influx"
+	opts.OutputFile = out
+
+	var b strings.Builder
+
+	err := Run(&b, opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read out lp: %v", err)
+	}
+
+	want := `junit_test,class=Cart,method=Pay,version=1.0.0,label=1.0.0\ rc ` +
+		`sum=2,mean=1,median=1,min=0.5,max=1.5,passed=2i,failed=0i,skipped=0i,errors=0i 1714557600000000000` + "\n"
+	if string(data) != want {
+		t.Fatalf("influx output mismatch\n--- want\n%s--- got\n%s", want, data)
+	}
+}
+
+func TestRun_ExportSeriesJSONLWithOverride(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTimestampedReport(t)
+	out := filepath.Join(dir, "out.jsonl")
+
+	stamp, err := ParseTimestamp("1700000000")
+	if err != nil {
+		t.Fatalf("ParseTimestamp failed: %v", err)
+	}
+
+	opts := defaultOptions()
+	opts.Directory = dir
+	opts.OutputFormat = "jsonl"
+	opts.OutputFile = out
+	opts.Timestamp = stamp
+
+	var b strings.Builder
+
+	err = Run(&b, opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read out jsonl: %v", err)
+	}
+
+	var point map[string]any
+
+	err = json.Unmarshal(data, &point)
+	if err != nil {
+		t.Fatalf("decode jsonl: %v", err)
+	}
+
+	if point["timestamp"] != "2023-11-14T22:13:20Z" {
+		t.Fatalf("unexpected timestamp: %v", point["timestamp"])
+	}
+
+	if point["median_seconds"] != 1.0 || point["passed"] != 2.0 {
+		t.Fatalf("unexpected values: %v", point)
+	}
+}
+
+func TestParseTimestampInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseTimestamp("yesterday")
+	if err == nil {
+		t.Fatalf("expected error for invalid timestamp")
+	}
+}
@@ -0,0 +1,80 @@
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+func TestReportTrends(t *testing.T) {
+	t.Parallel()
+
+	versions := []string{"1.0.0", "1.1.0", "1.2.0", "2.0.0"}
+	units := map[string]*unit{}
+
+	push := func(class string, status junit.Status, durations ...int) {
+		for idx, ms := range durations {
+			test := makeTest("testRun", "pkg."+class, status, time.Duration(ms)*time.Millisecond)
+			if ms < 0 {
+				continue
+			}
+
+			if unitVal, ok := units[class+":Run"]; ok {
+				unitVal.Push(versions[idx], test)
+
+				continue
+			}
+
+			unitVal := newUnit(versions[idx], test)
+			units[unitVal.FullName()] = &unitVal
+		}
+	}
+
+	push("Slow", junit.StatusPassed, 100, 110, 120, 130)
+	push("Fast", junit.StatusPassed, 200, 150, 100, 50)
+	push("Noisy", junit.StatusPassed, 100, 300, 90, 310)
+	push("Gap", junit.StatusPassed, 100, -1, -1, 400)
+	push("Single", junit.StatusPassed, 100, -1, -1, -1)
+
+	report := buildReport(units, versions, nil, defaultOptions())
+
+	trends := report.Trends()
+	if len(trends) != 4 {
+		t.Fatalf("expected trends for 4 units, got %+v", trends)
+	}
+
+	slow := trends[0]
+	if slow.Name != "Slow:Run" || !slow.Slower() || !slow.Steady() || slow.Slope != 10*time.Millisecond {
+		t.Fatalf("expected the steady slow-down first, got %+v", slow)
+	}
+
+	if slow.FastestVersion != "1.0.0" || slow.SlowestVersion != "2.0.0" || slow.Slowest != 130*time.Millisecond {
+		t.Fatalf("unexpected extremes: %+v", slow)
+	}
+
+	// two points always fit a line, so Gap is slower but not steady despite its larger slope
+	gap := trends[1]
+	if gap.Name != "Gap:Run" || gap.Points != 2 || gap.Steady() || gap.Slope != 100*time.Millisecond {
+		t.Fatalf("expected the gap trend second, got %+v", gap)
+	}
+
+	if trends[2].Name != "Noisy:Run" || trends[2].Steady() || trends[3].Name != "Fast:Run" || trends[3].Slower() {
+		t.Fatalf("unexpected order: %+v", trends)
+	}
+
+	var out bytes.Buffer
+
+	err := Render(&out, report, "trend")
+	if err != nil {
+		t.Fatalf("Render trend failed: %v", err)
+	}
+
+	for _, want := range []string{"| Slow:Run", "steadily slower", "+10ms", "1.0.0: 100ms", "2.0.0: 130ms", "steadily faster"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func versionOptions(constraint string, excludePrerelease bool, last int, order ...string) Options {
+	opts := defaultOptions()
+	opts.Directory = filepath.Join("..", "build")
+	opts.VersionConstraint = constraint
+	opts.ExcludePrerelease = excludePrerelease
+	opts.LastVersions = last
+	opts.VersionOrder = order
+
+	return opts
+}
+
+func TestSortVersions(t *testing.T) {
+	t.Parallel()
+
+	versions := []string{"feature-x", "7.1.0", "main", "10.0.0", "7.0.0", "release"}
+	sortVersions(versions, []string{"release", "main"})
+
+	if got := strings.Join(versions, ","); got != "release,main,7.0.0,7.1.0,10.0.0,feature-x" {
+		t.Fatalf("unexpected order: %s", got)
+	}
+}
+
+func TestSelectVersions(t *testing.T) {
+	t.Parallel()
+
+	versions := []string{"6.2.4", "7.x", "7.1.0", "7.3.0-beta1", "10.0.0-beta1", "main"}
+
+	cases := []struct {
+		opts Options
+		want string
+	}{
+		{opts: versionOptions("", false, 0), want: strings.Join(versions, ",")},
+		{opts: versionOptions(">= 7.0, < 10", false, 0), want: "7.x,7.1.0,7.3.0-beta1"},
+		{opts: versionOptions("~> 7.1", true, 0), want: "7.1.0"},
+		{opts: versionOptions("", true, 0), want: "6.2.4,7.x,7.1.0,main"},
+		{opts: versionOptions("", false, 2), want: "10.0.0-beta1,main"},
+		{opts: versionOptions(">= 7", true, 2), want: "7.x,7.1.0"},
+	}
+
+	for _, tc := range cases {
+		if got := strings.Join(selectVersions(versions, tc.opts), ","); got != tc.want {
+			t.Fatalf("%q prerelease=%v last=%d: expected %s, got %s",
+				tc.opts.VersionConstraint, !tc.opts.ExcludePrerelease, tc.opts.LastVersions, tc.want, got)
+		}
+	}
+}
+
+func TestAggregate_VersionSelection(t *testing.T) {
+	t.Parallel()
+
+	data, err := Load(context.Background(), filepath.Join("..", "build"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	report := Aggregate(data, versionOptions(">= 6.1, < 10", true, 3, "7.2.0"))
+
+	if got := strings.Join(report.Versions, ","); got != "6.2.4,7.0.0,7.1.0" {
+		t.Fatalf("unexpected versions: %s", got)
+	}
+
+	for _, name := range []string{"State:RefreshInTransaction", "Cart:EagerLoaderPay"} {
+		found := false
+
+		for _, row := range report.Texts() {
+			found = found || row[0] == name
+		}
+
+		if !found {
+			t.Fatalf("expected %s among the rows", name)
+		}
+	}
+
+	err = versionOptions(">= seven", false, 0).Validate()
+	if !errors.Is(err, ErrInvalidConstraint) {
+		t.Fatalf("expected ErrInvalidConstraint, got %v", err)
+	}
+
+	err = RunContext(context.Background(), nil, versionOptions("<<1", false, 0))
+	if !errors.Is(err, ErrInvalidConstraint) {
+		t.Fatalf("expected RunContext to validate the constraint, got %v", err)
+	}
+
+	err = versionOptions("", false, -2).Validate()
+	if !errors.Is(err, ErrInvalidLastVersions) {
+		t.Fatalf("expected ErrInvalidLastVersions, got %v", err)
+	}
+}
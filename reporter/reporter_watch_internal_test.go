@@ -0,0 +1,108 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReportCache(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := writeDiffReport(t, dir, "junit-1.0.0.xml", `<testcase classname="a.CartTest" name="testPay" time="1"/>`)
+
+	cache := NewReportCache()
+	parsed := 0
+	parse := func(path string) ([]loadedFile, []*ReportError, error) {
+		parsed++
+
+		return loadPath(t.Context(), path, false)
+	}
+
+	for range 2 {
+		files, _, err := cache.load(path, parse)
+		if err != nil || len(files) != 1 || len(files[0].Tests) != 1 {
+			t.Fatalf("unexpected load: %v %+v", err, files)
+		}
+	}
+
+	if parsed != 1 {
+		t.Fatalf("expected the unchanged file to be parsed once, got %d", parsed)
+	}
+
+	writeDiffReport(t, dir, "junit-1.0.0.xml",
+		`<testcase classname="a.CartTest" name="testPay" time="1"/>`,
+		`<testcase classname="a.CartTest" name="testPay" time="2"/>`)
+
+	files, _, err := cache.load(path, parse)
+	if err != nil || parsed != 2 || len(files[0].Tests) != 2 {
+		t.Fatalf("expected the changed file to be parsed again: %v, %d parses", err, parsed)
+	}
+
+	cache.retain(nil)
+
+	if len(cache.entries) != 0 {
+		t.Fatalf("expected retain to drop files that are gone: %v", cache.entries)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeDiffReport(t, dir, "junit-1.0.0.xml", `<testcase classname="a.CartTest" name="testPay" time="1"/>`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var events []WatchEvent
+
+	opts := defaultOptions()
+	opts.Directory = dir
+
+	err := Watch(ctx, opts, 10*time.Millisecond, func(event WatchEvent) error {
+		events = append(events, event)
+
+		switch len(events) {
+		case 1:
+			writeDiffReport(t, dir, "junit-2.0.0.xml", `<testcase classname="a.CartTest" name="testPay" time="2"/>`)
+		case 2:
+			cancel()
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	if len(events) != 2 || events[0].Changed != nil {
+		t.Fatalf("expected an initial and an update event, got %+v", events)
+	}
+
+	update := events[1]
+	if update.Err != nil || strings.Join(update.Report.Versions, ",") != "1.0.0,2.0.0" {
+		t.Fatalf("unexpected update: %+v", update)
+	}
+
+	if len(update.Changed) != 1 || update.Changed[0] != filepath.Join(dir, "junit-2.0.0.xml") {
+		t.Fatalf("unexpected changed files: %v", update.Changed)
+	}
+
+	stdinOpts := defaultOptions()
+	stdinOpts.Inputs = []string{"7.0=-"}
+
+	err = Watch(ctx, stdinOpts, time.Second, func(WatchEvent) error { return nil })
+	if !errors.Is(err, ErrWatchStdin) {
+		t.Fatalf("expected ErrWatchStdin, got %v", err)
+	}
+
+	err = Watch(ctx, stdinOpts, 0, func(WatchEvent) error { return nil })
+	if !errors.Is(err, ErrInvalidWatchInterval) {
+		t.Fatalf("expected ErrInvalidWatchInterval, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package reporter
+
+import (
+	"archive/zip"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readZipPart(t *testing.T, archive *zip.ReadCloser, name string) string {
+	t.Helper()
+
+	for _, file := range archive.File {
+		if file.Name != name {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", name, err)
+		}
+		defer rc.Close()
+
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+
+		return string(data)
+	}
+
+	t.Fatalf("part %s not found", name)
+
+	return ""
+}
+
+func TestRun_ExportXLSX(t *testing.T) {
+	t.Parallel()
+	td := t.TempDir()
+	out := filepath.Join(td, "out.xlsx")
+	opts := defaultOptions()
+	opts.Directory = filepath.Join("..", "build")
+	opts.OutputFormat = "xlsx"
+	opts.OutputFile = out
+
+	var b strings.Builder
+
+	err := Run(&b, opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	archive, err := zip.OpenReader(out)
+	if err != nil {
+		t.Fatalf("open xlsx: %v", err)
+	}
+	defer archive.Close()
+
+	workbook := readZipPart(t, archive, "xl/workbook.xml")
+	for _, name := range []string{"Sum", "Mean", "Median", "Min", "Max"} {
+		if !strings.Contains(workbook, `<sheet name="`+name+`"`) {
+			t.Fatalf("workbook has no %s sheet", name)
+		}
+	}
+
+	median := readZipPart(t, archive, "xl/worksheets/sheet3.xml")
+	for _, want := range []string{
+		`state="frozen"`,
+		`<c r="G3" s="2"><v>0.659987</v></c>`,
+		`<conditionalFormatting sqref="C2:J15">`,
+	} {
+		if !strings.Contains(median, want) {
+			t.Fatalf("median sheet does not contain %q", want)
+		}
+	}
+}
+
+func TestXLSXColumn(t *testing.T) {
+	t.Parallel()
+
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for idx, want := range tests {
+		if got := xlsxColumn(idx); got != want {
+			t.Fatalf("xlsxColumn(%d) = %q; want %q", idx, got, want)
+		}
+	}
+}
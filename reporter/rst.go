@@ -0,0 +1,136 @@
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// rstPadding is one space on each side of a grid table cell.
+const rstPadding = 2
+
+// rstEscape escapes inline markup and a leading bullet character, so the "-" placeholder
+// is not parsed as an empty bullet list.
+func rstEscape(val string) string {
+	val = strings.NewReplacer(`\`, `\\`, `*`, `\*`, "`", "\\`", `|`, `\|`).Replace(val)
+	if strings.HasPrefix(val, "-") || strings.HasPrefix(val, "+") {
+		val = `\` + val
+	}
+
+	return val
+}
+
+func rstEscapeRows(rows [][]string) [][]string {
+	escaped := make([][]string, 0, len(rows))
+
+	for _, row := range rows {
+		values := make([]string, 0, len(row))
+		for _, val := range row {
+			values = append(values, rstEscape(val))
+		}
+
+		escaped = append(escaped, values)
+	}
+
+	return escaped
+}
+
+// writeRSTGrid writes a reStructuredText grid table; with alignRight the values of all but the
+// first column are aligned to the right.
+func writeRSTGrid(w io.Writer, columns []string, rows [][]string, alignRight bool) error {
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		widths[i] = utf8.RuneCountInString(column)
+	}
+
+	for _, row := range rows {
+		for i := range min(len(row), len(widths)) {
+			widths[i] = max(widths[i], utf8.RuneCountInString(row[i]))
+		}
+	}
+
+	border := func(fill string) string {
+		var sb strings.Builder
+
+		for _, width := range widths {
+			sb.WriteString("+")
+			sb.WriteString(strings.Repeat(fill, width+rstPadding))
+		}
+
+		return sb.String() + "+\n"
+	}
+
+	line := func(values []string, right bool) string {
+		var sb strings.Builder
+
+		for i, width := range widths {
+			val := ""
+			if i < len(values) {
+				val = values[i]
+			}
+
+			pad := strings.Repeat(" ", width-utf8.RuneCountInString(val))
+
+			sb.WriteString("| ")
+
+			if right && i > 0 {
+				sb.WriteString(pad + val)
+			} else {
+				sb.WriteString(val + pad)
+			}
+
+			sb.WriteString(" ")
+		}
+
+		return sb.String() + "|\n"
+	}
+
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(border("-"))
+	bw.WriteString(line(columns, false))
+	bw.WriteString(border("="))
+
+	for _, row := range rows {
+		bw.WriteString(line(row, alignRight))
+		bw.WriteString(border("-"))
+	}
+
+	err := bw.Flush()
+	if err != nil {
+		return fmt.Errorf("write rst: %w", err)
+	}
+
+	return nil
+}
+
+// writeRSTList writes a reStructuredText list-table directive, which is easier to diff than a grid table.
+func writeRSTList(w io.Writer, columns []string, rows [][]string) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, ".. list-table::")
+	fmt.Fprintln(bw, "   :header-rows: 1")
+	fmt.Fprintln(bw, "   :stub-columns: 1")
+
+	for _, row := range append([][]string{columns}, rows...) {
+		fmt.Fprintln(bw)
+
+		for i, val := range row {
+			prefix := "     - "
+			if i == 0 {
+				prefix = "   * - "
+			}
+
+			fmt.Fprintf(bw, "%s%s\n", prefix, val)
+		}
+	}
+
+	err := bw.Flush()
+	if err != nil {
+		return fmt.Errorf("write rst: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package reporter
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/montanaflynn/stats"
+)
+
+var (
+	// ErrInvalidSort is returned by Options.Validate for a malformed sort key or order, and by
+	// Options.ValidateSort for a sort key naming a version that is not in the report.
+	ErrInvalidSort = errors.New("invalid sort key")
+	// ErrInvalidTop is returned by Options.Validate for a negative Top.
+	ErrInvalidTop = errors.New("number of top units must not be negative")
+)
+
+// SortKey is the value units are ordered by, see Options.SortBy.
+type SortKey string
+
+const (
+	// SortName orders units by name, the default.
+	SortName SortKey = "name"
+	// SortDuration orders units by their duration in a version, the last one by default.
+	SortDuration SortKey = "duration"
+	// SortDelta orders units by the change of their duration between two versions, the
+	// last two by default.
+	SortDelta SortKey = "delta"
+	// SortVariance orders units by the variance of their durations across versions.
+	SortVariance SortKey = "variance"
+	// SortFailures orders units by their failed and errored samples across versions.
+	SortFailures SortKey = "failures"
+)
+
+const (
+	sortAscending  = "asc"
+	sortDescending = "desc"
+	deltaSeparator = ".."
+)
+
+// sortSpec is a parsed Options.SortBy together with its direction.
+type sortSpec struct {
+	Key      SortKey
+	Versions []string
+	Desc     bool
+}
+
+// parseSort parses a "key[:versions]" sort spec, e.g. "duration:7.1.0" or
+// "delta:7.0.0..7.1.0". The order is "asc" or "desc"; when empty, names are sorted
+// ascending and the other keys descending, so the slowest or most regressed units come first.
+func parseSort(spec, order string) (sortSpec, error) {
+	name, arg, hasArg := strings.Cut(spec, ":")
+	parsed := sortSpec{Key: SortKey(name), Versions: nil, Desc: false}
+
+	switch parsed.Key {
+	case "":
+		parsed.Key = SortName
+	case SortName, SortVariance, SortFailures:
+		if hasArg {
+			return parsed, fmt.Errorf("%w %q: %s takes no versions", ErrInvalidSort, spec, name)
+		}
+	case SortDuration:
+		if hasArg {
+			parsed.Versions = []string{arg}
+		}
+	case SortDelta:
+		if hasArg {
+			oldVer, newVer, ok := strings.Cut(arg, deltaSeparator)
+			if !ok || oldVer == "" || newVer == "" {
+				return parsed, fmt.Errorf("%w %q: expected delta:OLD..NEW", ErrInvalidSort, spec)
+			}
+
+			parsed.Versions = []string{oldVer, newVer}
+		}
+	default:
+		return parsed, fmt.Errorf("%w %q", ErrInvalidSort, spec)
+	}
+
+	if hasArg && arg == "" {
+		return parsed, fmt.Errorf("%w %q: empty version", ErrInvalidSort, spec)
+	}
+
+	switch order {
+	case "":
+		parsed.Desc = parsed.Key != SortName
+	case sortAscending, sortDescending:
+		parsed.Desc = order == sortDescending
+	default:
+		return parsed, fmt.Errorf("%w: order %q, expected %s or %s", ErrInvalidSort, order, sortAscending, sortDescending)
+	}
+
+	return parsed, nil
+}
+
+// ValidateSort checks that the versions named by Options.SortBy, e.g. "duration:7.1.0", are
+// among versions, the versions of the report, since units cannot be ordered by a version they
+// have no samples of. Options.Validate checks the rest of the sort options before loading.
+func (o Options) ValidateSort(versions []string) error {
+	spec, err := parseSort(o.SortBy, o.SortOrder)
+	if err != nil {
+		return err
+	}
+
+	for _, ver := range spec.Versions {
+		if !slices.Contains(versions, ver) {
+			return fmt.Errorf("%w %q: %w: %q", ErrInvalidSort, o.SortBy, ErrUnknownVersion, ver)
+		}
+	}
+
+	return nil
+}
+
+// value returns the sort value of a unit, or false when the unit has none, e.g. no passed
+// samples in the compared versions.
+func (s sortSpec) value(unitVal *unit, versions []string, opts Options) (float64, bool) {
+	duration := func(ver string) (float64, bool) {
+		dur, err := unitVal.GetDuration(ver, opts.Ticks, opts.Median)
+
+		return float64(dur), err == nil
+	}
+
+	switch s.Key {
+	case SortDuration:
+		ver := s.pick(versions, 1)
+		if ver == nil {
+			return 0, false
+		}
+
+		return duration(ver[0])
+	case SortDelta:
+		pair := s.pick(versions, 2)
+		if pair == nil {
+			return 0, false
+		}
+
+		oldDur, oldOK := duration(pair[0])
+		newDur, newOK := duration(pair[1])
+
+		return newDur - oldDur, oldOK && newOK
+	case SortVariance:
+		var durations []float64
+
+		for _, ver := range versions {
+			if dur, ok := duration(ver); ok {
+				durations = append(durations, dur)
+			}
+		}
+
+		variance, err := stats.PopulationVariance(durations)
+
+		return variance, err == nil
+	case SortFailures:
+		failures := 0
+
+		for _, ver := range versions {
+			agg := unitVal.Aggregate(ver)
+			failures += agg.Failed + agg.Errors
+		}
+
+		return float64(failures), true
+	default:
+		return 0, false
+	}
+}
+
+// pick returns the versions named by the spec, or the last count versions of the report.
+func (s sortSpec) pick(versions []string, count int) []string {
+	if s.Versions != nil {
+		return s.Versions
+	}
+
+	if len(versions) < count {
+		return nil
+	}
+
+	return versions[len(versions)-count:]
+}
+
+// orderUnits returns the unit names in the order of Options.SortBy, keeping the first
+// Options.Top when positive. Units without a sort value follow the others, and ties are
+// broken by name.
+func orderUnits(units map[string]*unit, versions []string, opts Options) []string {
+	unitList := sortedUnitKeys(units)
+
+	// an invalid spec sorts by name, see Options.Validate
+	spec, _ := parseSort(opts.SortBy, opts.SortOrder)
+
+	if spec.Key == SortName && spec.Desc {
+		slices.Reverse(unitList)
+	}
+
+	if spec.Key != SortName {
+		type keyed struct {
+			value float64
+			ok    bool
+		}
+
+		values := make(map[string]keyed, len(unitList))
+
+		for _, unitKey := range unitList {
+			value, ok := spec.value(units[unitKey], versions, opts)
+			values[unitKey] = keyed{value: value, ok: ok}
+		}
+
+		slices.SortStableFunc(unitList, func(a, b string) int {
+			valA, valB := values[a], values[b]
+
+			switch {
+			case valA.ok != valB.ok && valA.ok:
+				return -1
+			case valA.ok != valB.ok:
+				return 1
+			case spec.Desc:
+				return cmp.Compare(valB.value, valA.value)
+			default:
+				return cmp.Compare(valA.value, valB.value)
+			}
+		})
+	}
+
+	if opts.Top > 0 && len(unitList) > opts.Top {
+		unitList = unitList[:opts.Top]
+	}
+
+	return unitList
+}
@@ -0,0 +1,233 @@
+package reporter
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+// suiteFrame is a testsuite element that is still open while decoding.
+type suiteFrame struct {
+	// index is the position of the suite in document order.
+	index int
+	// test is the open testcase of the suite, if any.
+	test *junit.Test
+}
+
+// decodeReport walks the testsuite and testcase elements of a JUnit document token by
+// token and passes every test case to visit as soon as it is closed, without building the
+// document tree. Only the fields used for aggregation are filled: Name, Classname, Duration
+// and Status; outputs, messages and properties are skipped, so what visit keeps grows
+// linearly with the number of test cases, however large their output is. Tests of nested suites
+// are visited too, in document order, rather than suite by suite like depthSuite;
+// aggregation does not depend on that order. The returned time is the report timestamp
+// chosen like suitesTimestamp. Decoding stops with the context error once ctx is done.
+func decodeReport(ctx context.Context, r io.Reader, visit func(junit.Test)) (time.Time, error) {
+	decoder := xml.NewDecoder(r)
+
+	var (
+		stack  []*suiteFrame
+		stamps []string
+	)
+
+	for {
+		err := ctx.Err()
+		if err != nil {
+			return time.Time{}, positionedError(decoder, fmt.Errorf("decode xml: %w", err))
+		}
+
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return time.Time{}, positionedError(decoder, fmt.Errorf("decode xml: %w", err))
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			stack, stamps, err = decodeStart(decoder, elem, stack, stamps)
+			if err != nil {
+				return time.Time{}, positionedError(decoder, err)
+			}
+		case xml.EndElement:
+			stack, err = decodeEnd(elem, stack, visit)
+			if err != nil {
+				return time.Time{}, positionedError(decoder, err)
+			}
+		}
+	}
+
+	return firstTimestamp(stamps), nil
+}
+
+func decodeStart(decoder *xml.Decoder, elem xml.StartElement, stack []*suiteFrame, stamps []string) ([]*suiteFrame, []string, error) {
+	if elem.Name.Local == "testsuite" {
+		stamps = append(stamps, xmlAttr(elem, "timestamp"))
+
+		return append(stack, &suiteFrame{index: len(stamps) - 1, test: nil}), stamps, nil
+	}
+
+	if len(stack) == 0 {
+		return stack, stamps, nil
+	}
+
+	top := stack[len(stack)-1]
+
+	switch {
+	case top.test != nil:
+		switch elem.Name.Local {
+		case "skipped":
+			top.test.Status = junit.StatusSkipped
+		case "failure":
+			top.test.Status = junit.StatusFailed
+		case "error":
+			top.test.Status = junit.StatusError
+		}
+
+		return stack, stamps, skipElement(decoder)
+	case elem.Name.Local == "testcase":
+		top.test = &junit.Test{
+			Name:       xmlAttr(elem, "name"),
+			Classname:  xmlAttr(elem, "classname"),
+			Duration:   parseJUnitDuration(xmlAttr(elem, "time")),
+			Status:     junit.StatusPassed,
+			Message:    "",
+			Error:      nil,
+			Properties: nil,
+			SystemOut:  "",
+			SystemErr:  "",
+		}
+
+		return stack, stamps, nil
+	case elem.Name.Local == "properties":
+		// a <properties> child replaces the suite attributes, including the timestamp
+		stamp, err := decodeProperties(decoder)
+		stamps[top.index] = stamp
+
+		return stack, stamps, err
+	default:
+		return stack, stamps, skipElement(decoder)
+	}
+}
+
+// decodeEnd closes the open testcase or testsuite. A test case needs a name, the first word
+// of which is the method of its unit.
+func decodeEnd(elem xml.EndElement, stack []*suiteFrame, visit func(junit.Test)) ([]*suiteFrame, error) {
+	if len(stack) == 0 {
+		return stack, nil
+	}
+
+	top := stack[len(stack)-1]
+
+	switch elem.Name.Local {
+	case "testcase":
+		if top.test == nil {
+			break
+		}
+
+		if strings.TrimSpace(top.test.Name) == "" {
+			return stack, fmt.Errorf("%w: class %q", ErrMissingTestName, top.test.Classname)
+		}
+
+		visit(*top.test)
+		top.test = nil
+	case "testsuite":
+		stack = stack[:len(stack)-1]
+	}
+
+	return stack, nil
+}
+
+// decodeProperties consumes a <properties> element and returns the value of its
+// "timestamp" property.
+func decodeProperties(decoder *xml.Decoder) (string, error) {
+	var stamp string
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return stamp, fmt.Errorf("decode xml: %w", err)
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			if elem.Name.Local == "property" && xmlAttr(elem, "name") == "timestamp" {
+				stamp = xmlAttr(elem, "value")
+			}
+
+			err = skipElement(decoder)
+			if err != nil {
+				return stamp, err
+			}
+		case xml.EndElement:
+			return stamp, nil
+		}
+	}
+}
+
+// positionedError locates err at the current decoder position; the path is added by the caller.
+func positionedError(decoder *xml.Decoder, err error) *ReportError {
+	line, column := decoder.InputPos()
+
+	return &ReportError{Path: "", Line: line, Column: column, Err: err}
+}
+
+func skipElement(decoder *xml.Decoder) error {
+	err := decoder.Skip()
+	if err != nil {
+		return fmt.Errorf("decode xml: %w", err)
+	}
+
+	return nil
+}
+
+func xmlAttr(elem xml.StartElement, name string) string {
+	for _, attr := range elem.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// firstTimestamp returns the first parsable suite timestamp in document order.
+func firstTimestamp(stamps []string) time.Time {
+	for _, raw := range stamps {
+		stamp, ok := parseSuiteTimestamp(raw)
+		if ok {
+			return stamp
+		}
+	}
+
+	return time.Time{}
+}
+
+// parseJUnitDuration reads a testcase "time" attribute the way go-junit does: seconds with
+// optional thousands separators, or a Go duration string.
+func parseJUnitDuration(raw string) time.Duration {
+	const microsPerSecond = 1e6
+
+	raw = strings.ReplaceAll(raw, ",", "")
+
+	seconds, err := strconv.ParseFloat(raw, 64)
+	if err == nil {
+		return time.Duration(seconds*microsPerSecond) * time.Microsecond
+	}
+
+	d, err := time.ParseDuration(raw)
+	if err == nil {
+		return d
+	}
+
+	return 0
+}
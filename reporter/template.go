@@ -0,0 +1,201 @@
+package reporter
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateData is the model passed to user templates given with Options.Template.
+type TemplateData struct {
+	// Versions are the report columns in display order.
+	Versions []string
+	// Units are the report rows in table order: sorted by Options.SortBy and
+	// Options.SortOrder, by name by default, and cut to Options.Top when it is set.
+	Units []TemplateUnit
+	// Stat names the statistic of TemplateCell.Value: "sum", "mean" or "median".
+	Stat string
+	Meta TemplateMeta
+}
+
+// TemplateMeta describes how the report was produced.
+type TemplateMeta struct {
+	Directory string
+	Files     []string
+	Generated time.Time
+	Ticks     bool
+	Group     bool
+	Major     bool
+	Median    bool
+	Rotate    bool
+	// Filtered is the number of units left out by filters.
+	Filtered int
+}
+
+// TemplateUnit is a single test with a cell per version.
+type TemplateUnit struct {
+	Name   string         `json:"name"`
+	Class  string         `json:"class"`
+	Method string         `json:"method"`
+	Cells  []TemplateCell `json:"cells"`
+}
+
+// TemplateCell holds the value of a unit in a version. Value and Text match the table
+// cell; the remaining statistics are computed over passed samples only.
+type TemplateCell struct {
+	Version string `json:"version"`
+	// OK is false when the unit is missing in the version or has non-passed samples.
+	OK      bool          `json:"ok"`
+	Value   time.Duration `json:"value"`
+	Text    string        `json:"text"`
+	Sum     time.Duration `json:"sum"`
+	Mean    time.Duration `json:"mean"`
+	Median  time.Duration `json:"median"`
+	Min     time.Duration `json:"min"`
+	Max     time.Duration `json:"max"`
+	Passed  int           `json:"passed"`
+	Failed  int           `json:"failed"`
+	Skipped int           `json:"skipped"`
+	Errors  int           `json:"errors"`
+}
+
+// Cell returns the cell of the given version or an empty cell when there is none.
+func (u TemplateUnit) Cell(ver string) TemplateCell {
+	for _, cell := range u.Cells {
+		if cell.Version == ver {
+			return cell
+		}
+	}
+
+	return TemplateCell{
+		Version: ver, OK: false, Value: 0, Text: ErrDash.Error(),
+		Sum: 0, Mean: 0, Median: 0, Min: 0, Max: 0, Passed: 0, Failed: 0, Skipped: 0, Errors: 0,
+	}
+}
+
+func templateStat(opts Options) string {
+	switch {
+	case !opts.Ticks:
+		return "sum"
+	case opts.Median:
+		return "median"
+	default:
+		return "mean"
+	}
+}
+
+func buildTemplateData(report *Report) TemplateData {
+	units, versions, opts := report.units, report.Versions, report.Options
+	format := newDurationFormat(opts)
+	data := TemplateData{
+		Versions: versions,
+		Units:    make([]TemplateUnit, 0, len(units)),
+		Stat:     templateStat(opts),
+		Meta: TemplateMeta{
+			Directory: opts.Directory,
+			Files:     report.Files,
+			Generated: time.Now(),
+			Ticks:     opts.Ticks,
+			Group:     opts.Group,
+			Major:     opts.Major,
+			Median:    opts.Median,
+			Rotate:    opts.Rotate,
+			Filtered:  report.Filtered,
+		},
+	}
+
+	for _, unitKey := range report.order {
+		unitVal := units[unitKey]
+		row := TemplateUnit{
+			Name:   unitVal.FullName(),
+			Class:  unitVal.ShortClass(),
+			Method: unitVal.ShortMethod(),
+			Cells:  make([]TemplateCell, 0, len(versions)),
+		}
+
+		for _, ver := range versions {
+			agg := unitVal.Aggregate(ver)
+			cell := TemplateCell{
+				Version: ver, OK: false, Value: 0, Text: ErrDash.Error(),
+				Sum: agg.Sum, Mean: agg.Mean, Median: agg.Median, Min: agg.Min, Max: agg.Max,
+				Passed: agg.Passed, Failed: agg.Failed, Skipped: agg.Skipped, Errors: agg.Errors,
+			}
+
+			dur, err := unitVal.GetDuration(ver, opts.Ticks, opts.Median)
+			if err == nil {
+				cell.OK, cell.Value, cell.Text = true, dur, format.Format(dur)
+			}
+
+			row.Cells = append(row.Cells, cell)
+		}
+
+		data.Units = append(data.Units, row)
+	}
+
+	return data
+}
+
+// templateFuncs are the helpers available in user templates; durations are formatted with
+// the duration format of the report.
+func templateFuncs(format DurationFormat) map[string]any {
+	return map[string]any{
+		"formatDuration": func(dur time.Duration) string {
+			if dur < 0 {
+				return format.FormatSigned(dur)
+			}
+
+			return format.Format(dur)
+		},
+		// delta formats the signed change from old to new, e.g. "+1.2s".
+		"delta": func(old, cur TemplateCell) string {
+			if !old.OK || !cur.OK {
+				return ErrDash.Error()
+			}
+
+			return format.FormatSigned(cur.Value - old.Value)
+		},
+		// percent formats the relative change from old to new, e.g. "-12.5%".
+		"percent": func(old, cur TemplateCell) string {
+			if !old.OK || !cur.OK || old.Value == 0 {
+				return ErrDash.Error()
+			}
+
+			return fmt.Sprintf("%+.1f%%", float64(cur.Value-old.Value)/float64(old.Value)*percentScale)
+		},
+	}
+}
+
+// renderTemplate renders the data with the template file. Files with an .html or .htm
+// extension are parsed with html/template so values are escaped for HTML.
+func renderTemplate(w io.Writer, tmplPath string, data TemplateData, format DurationFormat) error {
+	name := filepath.Base(tmplPath)
+
+	var err error
+
+	switch strings.ToLower(filepath.Ext(tmplPath)) {
+	case ".html", ".htm":
+		var tmpl *htmltemplate.Template
+
+		tmpl, err = htmltemplate.New(name).Funcs(templateFuncs(format)).ParseFiles(tmplPath)
+		if err == nil {
+			err = tmpl.Execute(w, data)
+		}
+	default:
+		var tmpl *template.Template
+
+		tmpl, err = template.New(name).Funcs(templateFuncs(format)).ParseFiles(tmplPath)
+		if err == nil {
+			err = tmpl.Execute(w, data)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("render template %s: %w", tmplPath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const influxMeasurement = "junit_test"
+
+// ParseTimestamp parses a CLI timestamp override given either as RFC 3339
+// or as unix seconds.
+func ParseTimestamp(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	secs, err := strconv.ParseInt(raw, 10, 64)
+	if err == nil {
+		return time.Unix(secs, 0).UTC(), nil
+	}
+
+	stamp, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidTimestamp, raw)
+	}
+
+	return stamp, nil
+}
+
+// seriesTime returns the timestamp of a point: the override when set,
+// otherwise the timestamp of the source report.
+func seriesTime(agg UnitStats, override time.Time) time.Time {
+	if !override.IsZero() {
+		return override
+	}
+
+	return agg.Time
+}
+
+// newInfluxTagEscaper escapes tag keys and values of the line protocol.
+func newInfluxTagEscaper() *strings.Replacer {
+	return strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `, "\n", `\n`)
+}
+
+// writeInflux writes one InfluxDB line protocol point per unit, version and source report.
+// Durations are float fields in seconds and are omitted when no sample passed.
+func writeInflux(w io.Writer, aggs []UnitStats, override time.Time) error {
+	bw := bufio.NewWriter(w)
+	esc := newInfluxTagEscaper()
+
+	for _, agg := range aggs {
+		bw.WriteString(influxMeasurement)
+
+		for _, tag := range [][2]string{{"class", agg.Class}, {"method", agg.Method}, {"version", agg.Version}, {"label", agg.Label}} {
+			if tag[1] == "" {
+				continue
+			}
+
+			fmt.Fprintf(bw, ",%s=%s", tag[0], esc.Replace(tag[1]))
+		}
+
+		var fields []string
+
+		if agg.HasDurations() {
+			fields = append(fields,
+				"sum="+formatSeconds(agg.Sum),
+				"mean="+formatSeconds(agg.Mean),
+				"median="+formatSeconds(agg.Median),
+				"min="+formatSeconds(agg.Min),
+				"max="+formatSeconds(agg.Max),
+			)
+		}
+
+		fields = append(fields,
+			fmt.Sprintf("passed=%di", agg.Passed),
+			fmt.Sprintf("failed=%di", agg.Failed),
+			fmt.Sprintf("skipped=%di", agg.Skipped),
+			fmt.Sprintf("errors=%di", agg.Errors),
+		)
+
+		bw.WriteByte(' ')
+		bw.WriteString(strings.Join(fields, ","))
+
+		if stamp := seriesTime(agg, override); !stamp.IsZero() {
+			fmt.Fprintf(bw, " %d", stamp.UnixNano())
+		}
+
+		bw.WriteByte('\n')
+	}
+
+	err := bw.Flush()
+	if err != nil {
+		return fmt.Errorf("write influx: %w", err)
+	}
+
+	return nil
+}
+
+// seriesPoint is the JSON representation of a point in the generic time-series export.
+type seriesPoint struct {
+	Class     string   `json:"class"`
+	Method    string   `json:"method"`
+	Name      string   `json:"name"`
+	Version   string   `json:"version"`
+	Label     string   `json:"label"`
+	Timestamp *string  `json:"timestamp"`
+	Sum       *float64 `json:"sum_seconds"`
+	Mean      *float64 `json:"mean_seconds"`
+	Median    *float64 `json:"median_seconds"`
+	Min       *float64 `json:"min_seconds"`
+	Max       *float64 `json:"max_seconds"`
+	Passed    int      `json:"passed"`
+	Failed    int      `json:"failed"`
+	Skipped   int      `json:"skipped"`
+	Errors    int      `json:"errors"`
+}
+
+func secondsPtr(dur time.Duration, ok bool) *float64 {
+	if !ok {
+		return nil
+	}
+
+	secs := dur.Seconds()
+
+	return &secs
+}
+
+// writeSeriesJSONL writes the same points as writeInflux as JSON lines, one object per point,
+// for time-series stores that ingest JSON.
+func writeSeriesJSONL(w io.Writer, aggs []UnitStats, override time.Time) error {
+	enc := json.NewEncoder(w)
+
+	for _, agg := range aggs {
+		var stamp *string
+
+		if ts := seriesTime(agg, override); !ts.IsZero() {
+			formatted := ts.UTC().Format(time.RFC3339Nano)
+			stamp = &formatted
+		}
+
+		ok := agg.HasDurations()
+
+		err := enc.Encode(seriesPoint{
+			Class:     agg.Class,
+			Method:    agg.Method,
+			Name:      agg.Name,
+			Version:   agg.Version,
+			Label:     agg.Label,
+			Timestamp: stamp,
+			Sum:       secondsPtr(agg.Sum, ok),
+			Mean:      secondsPtr(agg.Mean, ok),
+			Median:    secondsPtr(agg.Median, ok),
+			Min:       secondsPtr(agg.Min, ok),
+			Max:       secondsPtr(agg.Max, ok),
+			Passed:    agg.Passed,
+			Failed:    agg.Failed,
+			Skipped:   agg.Skipped,
+			Errors:    agg.Errors,
+		})
+		if err != nil {
+			return fmt.Errorf("encode series point: %w", err)
+		}
+	}
+
+	return nil
+}
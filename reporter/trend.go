@@ -0,0 +1,181 @@
+package reporter
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	// minTrendPoints are the versions a line needs to be fitted.
+	minTrendPoints = 2
+	// steadyPoints are the versions a steady trend needs; two points always fit a line.
+	steadyPoints = 3
+	// steadyFit is the smallest coefficient of determination of a steady trend.
+	steadyFit = 0.8
+)
+
+// UnitTrend is a least-squares line fitted to the cell values of a unit across the
+// ordered versions of a report. Versions without a passed cell are left out of the fit
+// but keep their position, so a gap counts as a version step.
+type UnitTrend struct {
+	Name string `json:"name"`
+	// Points is the number of versions the line was fitted to.
+	Points int `json:"points"`
+	// Slope is the change of the value per version step.
+	Slope time.Duration `json:"slope"`
+	// Percent is Slope relative to the mean value of the fitted versions.
+	Percent float64 `json:"percent"`
+	// R2 is the coefficient of determination of the line, 1 for a perfectly linear trend.
+	R2             float64       `json:"r2"`
+	Fastest        time.Duration `json:"fastest"`
+	FastestVersion string        `json:"fastest_version"`
+	Slowest        time.Duration `json:"slowest"`
+	SlowestVersion string        `json:"slowest_version"`
+}
+
+// Slower reports whether the unit got slower across the versions.
+func (t UnitTrend) Slower() bool {
+	return t.Slope > 0
+}
+
+// Steady reports whether the values of at least three versions follow the line closely
+// enough to call the trend steady.
+func (t UnitTrend) Steady() bool {
+	return t.Points >= steadyPoints && t.R2 >= steadyFit
+}
+
+// Trends fits a trend to every unit with passed cells in at least two versions. Units
+// that steadily got slower come first, then the rest; both are ordered by the relative
+// slope, largest first.
+func (r *Report) Trends() []UnitTrend {
+	var trends []UnitTrend
+
+	for _, unitKey := range sortedUnitKeys(r.units) {
+		trend, ok := fitTrend(r.units[unitKey], r.Versions, r.Options)
+		if ok {
+			trends = append(trends, trend)
+		}
+	}
+
+	slices.SortStableFunc(trends, func(a, b UnitTrend) int {
+		aFirst, bFirst := a.Slower() && a.Steady(), b.Slower() && b.Steady()
+		if aFirst != bFirst {
+			if aFirst {
+				return -1
+			}
+
+			return 1
+		}
+
+		return cmp.Compare(b.Percent, a.Percent)
+	})
+
+	return trends
+}
+
+func fitTrend(unitVal *unit, versions []string, opts Options) (UnitTrend, bool) {
+	trend := UnitTrend{
+		Name:           unitVal.FullName(),
+		Points:         0,
+		Slope:          0,
+		Percent:        0,
+		R2:             0,
+		Fastest:        0,
+		FastestVersion: "",
+		Slowest:        0,
+		SlowestVersion: "",
+	}
+
+	var xs, ys []float64
+
+	for idx, ver := range versions {
+		dur, err := unitVal.GetDuration(ver, opts.Ticks, opts.Median)
+		if err != nil {
+			continue
+		}
+
+		if trend.Points == 0 || dur < trend.Fastest {
+			trend.Fastest, trend.FastestVersion = dur, ver
+		}
+
+		if trend.Points == 0 || dur > trend.Slowest {
+			trend.Slowest, trend.SlowestVersion = dur, ver
+		}
+
+		trend.Points++
+
+		xs = append(xs, float64(idx))
+		ys = append(ys, float64(dur))
+	}
+
+	if trend.Points < minTrendPoints {
+		return trend, false
+	}
+
+	meanX, meanY := mean(xs), mean(ys)
+
+	var sxx, sxy, syy float64
+
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+
+	slope := sxy / sxx
+	trend.Slope = time.Duration(math.Round(slope))
+
+	if meanY > 0 {
+		trend.Percent = slope / meanY * percentScale
+	}
+
+	// identical values lie on the line exactly
+	trend.R2 = 1
+	if syy > 0 {
+		trend.R2 = sxy * sxy / (sxx * syy)
+	}
+
+	return trend, true
+}
+
+// writeTrend writes the trends of a report as a table, see Report.Trends.
+func writeTrend(w io.Writer, trends []UnitTrend, format DurationFormat, alignRight bool) error {
+	columns := []string{"Name", "Trend", "Per version", "Change", "R²", "Fastest", "Slowest"}
+	rows := make([][]string, 0, len(trends))
+
+	for _, trend := range trends {
+		rows = append(rows, []string{
+			trend.Name,
+			trendLabel(trend),
+			format.FormatSigned(trend.Slope),
+			fmt.Sprintf("%+.1f%%", trend.Percent),
+			fmt.Sprintf("%.2f", trend.R2),
+			trend.FastestVersion + ": " + format.Format(trend.Fastest),
+			trend.SlowestVersion + ": " + format.Format(trend.Slowest),
+		})
+	}
+
+	return renderAlignedTable(w, columns, rows, alignRight)
+}
+
+func trendLabel(trend UnitTrend) string {
+	label := "faster"
+
+	switch {
+	case trend.Slope == 0:
+		return "flat"
+	case trend.Slower():
+		label = "slower"
+	}
+
+	if trend.Steady() {
+		return "steadily " + label
+	}
+
+	return label
+}
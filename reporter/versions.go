@@ -0,0 +1,135 @@
+package reporter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+var (
+	// ErrInvalidConstraint is returned by Options.Validate for a malformed version constraint.
+	ErrInvalidConstraint = errors.New("invalid version constraint")
+	// ErrInvalidLastVersions is returned by Options.Validate for a negative LastVersions.
+	ErrInvalidLastVersions = errors.New("number of last versions must not be negative")
+)
+
+// Validate checks the options that are parsed while aggregating and rendering, so that
+// mistakes are reported before reports are loaded.
+func (o Options) Validate() error {
+	if o.VersionConstraint != "" {
+		_, err := version.NewConstraint(o.VersionConstraint)
+		if err != nil {
+			return fmt.Errorf("%w %q: %w", ErrInvalidConstraint, o.VersionConstraint, err)
+		}
+	}
+
+	if o.LastVersions < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidLastVersions, o.LastVersions)
+	}
+
+	_, err := parseSort(o.SortBy, o.SortOrder)
+	if err != nil {
+		return err
+	}
+
+	if o.Top < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidTop, o.Top)
+	}
+
+	_, err = ParseDurationFormat(o.DurationUnit, o.DurationPrecision)
+
+	return err
+}
+
+// parseVersion parses a version label, treating 'x' as zero like CompareVersions.
+func parseVersion(label string) (*version.Version, bool) {
+	ver, err := version.NewVersion(strings.ReplaceAll(label, "x", "0"))
+
+	return ver, err == nil
+}
+
+// sortVersions orders versions by their position in order, then the versions that are not
+// listed semantically with CompareVersions.
+func sortVersions(versions, order []string) {
+	rank := func(ver string) int {
+		if idx := slices.Index(order, ver); idx >= 0 {
+			return idx
+		}
+
+		return len(order)
+	}
+
+	slices.SortStableFunc(versions, func(a, b string) int {
+		rankA, rankB := rank(a), rank(b)
+
+		switch {
+		case rankA != rankB:
+			return rankA - rankB
+		case CompareVersions(a, b):
+			return -1
+		case CompareVersions(b, a):
+			return 1
+		default:
+			return 0
+		}
+	})
+}
+
+// selectVersions keeps the sorted versions selected by the options: those matching
+// VersionConstraint and, with ExcludePrerelease, not pre-releases, and of those the last
+// LastVersions. The constraint is checked against the version without its pre-release, so
+// ">= 7.0, < 8" selects 7.3.0-beta1; labels that are not versions never match it.
+func selectVersions(versions []string, opts Options) []string {
+	var constraint version.Constraints
+
+	if opts.VersionConstraint != "" {
+		// an invalid constraint matches nothing, see Options.Validate
+		constraint, _ = version.NewConstraint(opts.VersionConstraint)
+	}
+
+	selected := make([]string, 0, len(versions))
+
+	for _, label := range versions {
+		ver, ok := parseVersion(label)
+
+		switch {
+		case opts.ExcludePrerelease && ok && ver.Prerelease() != "":
+			continue
+		case opts.VersionConstraint != "" && (!ok || constraint == nil || !constraint.Check(ver.Core())):
+			continue
+		}
+
+		selected = append(selected, label)
+	}
+
+	if opts.LastVersions > 0 && len(selected) > opts.LastVersions {
+		selected = selected[len(selected)-opts.LastVersions:]
+	}
+
+	return selected
+}
+
+// selectFiles keeps the files of the versions selected by opts, see selectVersions.
+func selectFiles(files []loadedFile, opts Options) []loadedFile {
+	if opts.VersionConstraint == "" && !opts.ExcludePrerelease && opts.LastVersions <= 0 {
+		return files
+	}
+
+	var versions []string
+
+	for _, file := range files {
+		if ver := file.version(opts.Group, opts.Major); !slices.Contains(versions, ver) {
+			versions = append(versions, ver)
+		}
+	}
+
+	sortVersions(versions, opts.VersionOrder)
+	selected := selectVersions(versions, opts)
+
+	return slices.DeleteFunc(slices.Clone(files), func(file loadedFile) bool {
+		return !slices.Contains(selected, file.version(opts.Group, opts.Major))
+	})
+}
@@ -0,0 +1,235 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrWatchStdin is returned by Watch and NewDashboard for the "-" source, which can be read
+// only once.
+var ErrWatchStdin = errors.New("stdin cannot be watched or served")
+
+// ErrInvalidWatchInterval is returned by Watch for an interval that is not positive.
+var ErrInvalidWatchInterval = errors.New("watch interval must be positive")
+
+// fileStamp identifies a version of a file on disk.
+type fileStamp struct {
+	size    int64
+	modTime int64
+}
+
+func statFile(path string) (fileStamp, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{size: 0, modTime: 0}, false
+	}
+
+	return fileStamp{size: info.Size(), modTime: info.ModTime().UnixNano()}, true
+}
+
+type cacheEntry struct {
+	stamp fileStamp
+	files []loadedFile
+}
+
+// ReportCache keeps parsed reports by path, so a Loader with a cache parses only the files
+// that are new or changed in size or modification time since they were loaded last. It is
+// safe for concurrent use.
+type ReportCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// NewReportCache returns an empty cache.
+func NewReportCache() *ReportCache {
+	return &ReportCache{mu: sync.Mutex{}, entries: map[string]cacheEntry{}}
+}
+
+// load returns the reports of the file at path from the cache, or parses them with parse
+// and caches them. Files that fail to parse and archives with skipped entries are not
+// cached, so they are retried.
+func (c *ReportCache) load(path string, parse loadFunc) ([]loadedFile, []*ReportError, error) {
+	stamp, ok := statFile(path)
+	if !ok {
+		return parse(path)
+	}
+
+	c.mu.Lock()
+	entry, hit := c.entries[path]
+	c.mu.Unlock()
+
+	if hit && entry.stamp == stamp {
+		return slices.Clone(entry.files), nil, nil
+	}
+
+	files, skipped, err := parse(path)
+	if err != nil || len(skipped) > 0 {
+		return files, skipped, err
+	}
+
+	c.mu.Lock()
+	c.entries[path] = cacheEntry{stamp: stamp, files: slices.Clone(files)}
+	c.mu.Unlock()
+
+	return files, nil, nil
+}
+
+// retain drops the reports of files that are no longer among the inputs.
+func (c *ReportCache) retain(inputs []input) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for path := range c.entries {
+		if !slices.ContainsFunc(inputs, func(in input) bool { return in.path == path }) {
+			delete(c.entries, path)
+		}
+	}
+}
+
+// WatchEvent is passed to the callback of Watch after every load.
+type WatchEvent struct {
+	// Report is nil when loading failed with Err.
+	Report *Report
+	Err    error
+	// Changed are the report files added, changed or removed since the previous event;
+	// empty for the first one.
+	Changed []string
+	// Skipped are the reports left out with Options.KeepGoing.
+	Skipped []*ReportError
+}
+
+// rereadableSources returns the report sources of opts, or ErrWatchStdin when one of them
+// is stdin, which cannot be loaded again.
+func rereadableSources(opts Options) ([]string, error) {
+	sources := opts.Inputs
+	if len(sources) == 0 {
+		sources = []string{opts.Directory}
+	}
+
+	for _, spec := range sources {
+		if parseSource(spec).path == stdinSource {
+			return nil, ErrWatchStdin
+		}
+	}
+
+	return sources, nil
+}
+
+// Watch loads the reports of opts and passes the report to update, then checks the sources
+// every interval and loads them again when report files are added, changed or removed and
+// stay unchanged for another interval. Only those files are parsed again. A failed load is
+// passed to update as well, and retried once the files change. Watch returns when ctx is
+// done or update returns an error, ErrWatchStdin for stdin sources and
+// ErrInvalidWatchInterval for an interval that is not positive.
+func Watch(ctx context.Context, opts Options, interval time.Duration, update func(WatchEvent) error) error {
+	if interval <= 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidWatchInterval, interval)
+	}
+
+	sources, err := rereadableSources(opts)
+	if err != nil {
+		return err
+	}
+
+	loader := Loader{Jobs: opts.Jobs, Progress: opts.Progress, Stdin: nil, KeepGoing: opts.KeepGoing, Cache: NewReportCache()}
+	ticker := time.NewTicker(interval)
+
+	defer ticker.Stop()
+
+	var (
+		stamps  map[string]fileStamp
+		pending map[string]fileStamp
+		first   = true
+	)
+
+	for {
+		current := sourceStamps(sources)
+
+		// a change is loaded once the files stop changing for an interval, so reports that
+		// are still being written are not parsed half-way
+		settled := maps.Equal(current, pending)
+		pending = current
+
+		if first || (settled && !maps.Equal(current, stamps)) {
+			event := WatchEvent{Report: nil, Err: nil, Changed: nil, Skipped: nil}
+			if !first {
+				event.Changed = changedFiles(stamps, current)
+			}
+
+			data, err := loader.Load(ctx, sources...)
+			if err == nil {
+				event.Report = Aggregate(data, opts)
+				event.Skipped = data.Errors()
+				err = opts.ValidateSort(event.Report.Versions)
+			}
+
+			if err != nil {
+				event.Report, event.Err = nil, err
+			}
+
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			err = update(event)
+			if err != nil {
+				return err
+			}
+
+			first, stamps = false, current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sourceStamps stats the report files of the sources; unreadable sources have none.
+func sourceStamps(sources []string) map[string]fileStamp {
+	stamps := map[string]fileStamp{}
+
+	for _, spec := range sources {
+		inputs, err := resolveSources([]string{spec})
+		if err != nil {
+			continue
+		}
+
+		for _, in := range inputs {
+			if stamp, ok := statFile(in.path); ok {
+				stamps[in.path] = stamp
+			}
+		}
+	}
+
+	return stamps
+}
+
+// changedFiles returns the paths whose stamps differ between old and cur, sorted.
+func changedFiles(old, cur map[string]fileStamp) []string {
+	var changed []string
+
+	for path, stamp := range cur {
+		if prev, ok := old[path]; !ok || prev != stamp {
+			changed = append(changed, path)
+		}
+	}
+
+	for path := range old {
+		if _, ok := cur[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+
+	slices.Sort(changed)
+
+	return changed
+}
@@ -0,0 +1,237 @@
+package reporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// xlsxRegressionRatio highlights a cell that is slower than the previous version by more than 10%.
+	xlsxRegressionRatio = "1.1"
+	xlsxNameWidth       = 32
+	xlsxValueWidth      = 14
+	xlsxLetters         = 26
+)
+
+// Style indexes of cellXfs in xlsxStyles.
+const (
+	xlsxStyleHeader  = 1
+	xlsxStyleSeconds = 2
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+%s</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="1"><numFmt numFmtId="164" formatCode="0.000&quot; s&quot;"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="3">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+</cellXfs>
+<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>
+<dxfs count="1"><dxf><font><color rgb="FF9C0006"/></font><fill><patternFill><bgColor rgb="FFFFC7CE"/></patternFill></fill></dxf></dxfs>
+</styleSheet>`
+
+// xlsxStat is a statistic rendered as a separate worksheet.
+type xlsxStat struct {
+	Name  string
+	Value func(agg UnitStats) time.Duration
+}
+
+func xlsxStats() []xlsxStat {
+	return []xlsxStat{
+		{Name: "Sum", Value: func(agg UnitStats) time.Duration { return agg.Sum }},
+		{Name: "Mean", Value: func(agg UnitStats) time.Duration { return agg.Mean }},
+		{Name: "Median", Value: func(agg UnitStats) time.Duration { return agg.Median }},
+		{Name: "Min", Value: func(agg UnitStats) time.Duration { return agg.Min }},
+		{Name: "Max", Value: func(agg UnitStats) time.Duration { return agg.Max }},
+	}
+}
+
+// xlsxColumn converts a zero-based column index to letters: 0 -> A, 26 -> AA.
+func xlsxColumn(idx int) string {
+	name := ""
+	for idx++; idx > 0; idx = (idx - 1) / xlsxLetters {
+		name = string(rune('A'+(idx-1)%xlsxLetters)) + name
+	}
+
+	return name
+}
+
+func xlsxRef(col, row int) string {
+	return xlsxColumn(col) + strconv.Itoa(row+1)
+}
+
+func xlsxEscape(val string) string {
+	var buf bytes.Buffer
+
+	_ = xml.EscapeText(&buf, []byte(val))
+
+	return buf.String()
+}
+
+func xlsxStringCell(sb *strings.Builder, col, row int, val string, style int) {
+	fmt.Fprintf(sb, `<c r="%s" s="%d" t="inlineStr"><is><t>%s</t></is></c>`, xlsxRef(col, row), style, xlsxEscape(val))
+}
+
+// xlsxSheet renders a worksheet with a frozen header and a conditional format marking
+// regressions against the previous version (previous column, or previous row when rotated).
+func xlsxSheet(units map[string]*unit, unitList, versions []string, stat xlsxStat, rotate bool) string {
+	corner, header, lines := "Name", versions, unitList
+	if rotate {
+		corner, header, lines = "Ver", unitList, versions
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	sb.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	sb.WriteString(`<sheetViews><sheetView workbookViewId="0">`)
+	sb.WriteString(`<pane xSplit="1" ySplit="1" topLeftCell="B2" activePane="bottomRight" state="frozen"/>`)
+	sb.WriteString(`</sheetView></sheetViews>`)
+	fmt.Fprintf(&sb, `<cols><col min="1" max="1" width="%d" customWidth="1"/>`, xlsxNameWidth)
+	fmt.Fprintf(&sb, `<col min="2" max="%d" width="%d" customWidth="1"/></cols>`, len(header)+1, xlsxValueWidth)
+	sb.WriteString(`<sheetData><row r="1">`)
+	xlsxStringCell(&sb, 0, 0, corner, xlsxStyleHeader)
+
+	for i, title := range header {
+		xlsxStringCell(&sb, i+1, 0, title, xlsxStyleHeader)
+	}
+
+	sb.WriteString(`</row>`)
+
+	for r, line := range lines {
+		row := r + 1
+
+		fmt.Fprintf(&sb, `<row r="%d">`, row+1)
+		xlsxStringCell(&sb, 0, row, line, 0)
+
+		for c, title := range header {
+			unitKey, ver := line, title
+			if rotate {
+				unitKey, ver = title, line
+			}
+
+			agg := units[unitKey].Aggregate(ver)
+			if !agg.HasDurations() || agg.Passed != agg.Total() {
+				xlsxStringCell(&sb, c+1, row, ErrDash.Error(), 0)
+
+				continue
+			}
+
+			fmt.Fprintf(&sb, `<c r="%s" s="%d"><v>%s</v></c>`, xlsxRef(c+1, row), xlsxStyleSeconds, formatSeconds(stat.Value(agg)))
+		}
+
+		sb.WriteString(`</row>`)
+	}
+
+	sb.WriteString(`</sheetData>`)
+	sb.WriteString(xlsxRegressionRule(len(header), len(lines), rotate))
+	sb.WriteString(`</worksheet>`)
+
+	return sb.String()
+}
+
+// xlsxRegressionRule marks cells slower than the same unit in the previous version.
+// The formula is relative to the top-left cell of the range, so it applies to every cell.
+func xlsxRegressionRule(cols, rows int, rotate bool) string {
+	firstCol, firstRow := 2, 1
+	if rotate {
+		firstCol, firstRow = 1, 2
+	}
+
+	if cols < firstCol || rows < firstRow {
+		return ""
+	}
+
+	first, last, prev := xlsxRef(firstCol, firstRow), xlsxRef(cols, rows), xlsxRef(1, 1)
+
+	return fmt.Sprintf(`<conditionalFormatting sqref="%[1]s:%[2]s"><cfRule type="expression" dxfId="0" priority="1">`+
+		`<formula>AND(ISNUMBER(%[1]s),ISNUMBER(%[3]s),%[1]s&gt;%[3]s*%[4]s)</formula></cfRule></conditionalFormatting>`,
+		first, last, prev, xlsxRegressionRatio)
+}
+
+// writeXLSX writes a workbook with one worksheet per statistic. Durations are numeric
+// cells in seconds, so spreadsheets can sort and chart them. Units are laid out in the
+// order of unitList.
+func writeXLSX(w io.Writer, units map[string]*unit, unitList, versions []string, rotate bool) error {
+	stats := xlsxStats()
+
+	var overrides, sheets, rels strings.Builder
+
+	parts := map[string]string{}
+
+	for i, stat := range stats {
+		name := fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)
+		parts[name] = xlsxSheet(units, unitList, versions, stat, rotate)
+
+		fmt.Fprintf(&overrides, `<Override PartName="/%s" `+
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`+"\n", name)
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, stat.Name, i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" `+
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" `+
+			`Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" `+
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(stats)+1)
+
+	parts["[Content_Types].xml"] = fmt.Sprintf(xlsxContentTypes, overrides.String())
+	parts["_rels/.rels"] = xlsxRootRels
+	parts["xl/styles.xml"] = xlsxStyles
+	parts["xl/workbook.xml"] = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets>` + sheets.String() + `</sheets></workbook>`
+	parts["xl/_rels/workbook.xml.rels"] = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		rels.String() + `</Relationships>`
+
+	order := []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"}
+	for i := range stats {
+		order = append(order, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+	}
+
+	zw := zip.NewWriter(w)
+
+	for _, name := range order {
+		part, err := zw.Create(name)
+		if err != nil {
+			return fmt.Errorf("create xlsx part %s: %w", name, err)
+		}
+
+		_, err = io.WriteString(part, parts[name])
+		if err != nil {
+			return fmt.Errorf("write xlsx part %s: %w", name, err)
+		}
+	}
+
+	err := zw.Close()
+	if err != nil {
+		return fmt.Errorf("close xlsx: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/bavix/junit-reporter/reporter"
+)
+
+const (
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 5 * time.Second
+)
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags := newReportFlags(fs)
+	addr := fs.String("addr", "127.0.0.1:8080", "Address the dashboard listens on")
+
+	setUsage(fs, "serve [flags] [[label=]path ...]",
+		"Serves an interactive dashboard and a JSON API (/api/units, /api/versions, /api/compare?a=&b=)\n"+
+			"over the reports. Reports are re-read on every request; the report flags select and group them,\n"+
+			"and -timeout bounds every request. Stdin (\"-\") cannot be served.")
+
+	_ = fs.Parse(args)
+
+	opts, err := flags.options(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	handler, err := reporter.NewDashboard(opts)
+	if err != nil {
+		return fmt.Errorf("serve: %w", err)
+	}
+
+	if *flags.timeout > 0 {
+		handler = withRequestTimeout(handler, *flags.timeout)
+	}
+
+	// -timeout applies to every request rather than to the whole server
+	ctx, cancel := commandContext(0)
+	defer cancel()
+
+	listener, err := new(net.ListenConfig).Listen(ctx, "tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancelShutdown()
+
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "serving dashboard on http://%s/\n", listener.Addr())
+
+	err = server.Serve(listener)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve: %w", err)
+	}
+
+	return nil
+}
+
+// withRequestTimeout cancels the context of every request once timeout passes.
+func withRequestTimeout(handler http.Handler, timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
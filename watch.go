@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bavix/junit-reporter/reporter"
+)
+
+const defaultWatchInterval = time.Second
+
+// runWatch renders the report whenever its reports change. On a terminal the output is
+// redrawn in place; otherwise the cells that changed are appended after a status line.
+func runWatch(ctx context.Context, opts reporter.Options, interval time.Duration) error {
+	redraw := isTerminal(os.Stdout)
+
+	var previous *reporter.Report
+
+	err := reporter.Watch(ctx, opts, interval, func(event reporter.WatchEvent) error {
+		var buf bytes.Buffer
+
+		if event.Err != nil {
+			fmt.Fprintf(&buf, "error: %v\n", event.Err)
+		}
+
+		switch {
+		case event.Report == nil:
+		case redraw || previous == nil:
+			err := reporter.Emit(&buf, event.Report)
+			if err != nil {
+				return fmt.Errorf("render report: %w", err)
+			}
+		default:
+			writeCellDiff(&buf, previous, event.Report)
+		}
+
+		for _, skipped := range event.Skipped {
+			fmt.Fprintf(&buf, "skipped %s\n", skipped)
+		}
+
+		status := watchStatus(event)
+
+		switch {
+		case redraw:
+			// move home and clear the screen, then draw the report and the status below it
+			fmt.Fprintf(os.Stdout, "\033[H\033[2J%s\n%s\n", buf.Bytes(), status)
+		case previous == nil:
+			_, _ = os.Stdout.Write(buf.Bytes())
+		default:
+			fmt.Fprintf(os.Stdout, "\n## %s\n\n%s", status, buf.Bytes())
+		}
+
+		if event.Report != nil {
+			previous = event.Report
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
+	return nil
+}
+
+func watchStatus(event reporter.WatchEvent) string {
+	status := "updated " + time.Now().Format(time.TimeOnly)
+
+	switch len(event.Changed) {
+	case 0:
+	case 1:
+		status += ", changed " + event.Changed[0]
+	default:
+		status += fmt.Sprintf(", %d reports changed", len(event.Changed))
+	}
+
+	return status + "; watching for changes, Ctrl-C to stop"
+}
+
+// writeCellDiff writes the columns and rows added ("+") and removed ("-") since old, and
+// the cells of the other rows that changed ("~"), as "label column: old -> new".
+func writeCellDiff(w io.Writer, old, cur *reporter.Report) {
+	oldRows := reportCells(old)
+	curRows := reportCells(cur)
+	changed := false
+
+	for _, columns := range []struct {
+		sign       string
+		from, into []string
+	}{{sign: "+", from: cur.Columns, into: old.Columns}, {sign: "-", from: old.Columns, into: cur.Columns}} {
+		for _, column := range columns.from[1:] {
+			if !slices.Contains(columns.into, column) {
+				fmt.Fprintf(w, "%s column %s\n", columns.sign, column)
+
+				changed = true
+			}
+		}
+	}
+
+	for _, row := range cur.Texts() {
+		label := row[0]
+
+		oldCells, ok := oldRows[label]
+		if !ok {
+			fmt.Fprintf(w, "+ %s\n", strings.Join(row, " "))
+
+			changed = true
+
+			continue
+		}
+
+		for idx, text := range row[1:] {
+			column := cur.Columns[idx+1]
+
+			oldText, ok := oldCells[column]
+			if ok && oldText == text {
+				continue
+			}
+
+			if !ok {
+				// a new column: only cells with a value are news
+				if text == reporter.ErrDash.Error() {
+					continue
+				}
+
+				oldText = reporter.ErrDash.Error()
+			}
+
+			fmt.Fprintf(w, "~ %s %s: %s -> %s\n", label, column, oldText, text)
+
+			changed = true
+		}
+	}
+
+	for _, row := range old.Texts() {
+		if _, ok := curRows[row[0]]; !ok {
+			fmt.Fprintf(w, "- %s\n", row[0])
+
+			changed = true
+		}
+	}
+
+	if !changed {
+		fmt.Fprintln(w, "no changes in the table")
+	}
+}
+
+// reportCells maps the row labels of the report to the cells of their columns.
+func reportCells(report *reporter.Report) map[string]map[string]string {
+	rows := map[string]map[string]string{}
+
+	for _, row := range report.Texts() {
+		cells := map[string]string{}
+		for idx, text := range row[1:] {
+			cells[report.Columns[idx+1]] = text
+		}
+
+		rows[row[0]] = cells
+	}
+
+	return rows
+}